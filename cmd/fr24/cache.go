@@ -0,0 +1,278 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	lib "github.com/igolaizola/fr24/pkg/flightradar"
+	"github.com/klauspost/compress/zstd"
+	"github.com/peterbourgon/ff/v3/ffcli"
+)
+
+func cmdCache() *ffcli.Command {
+	fs := flag.NewFlagSet("cache", flag.ExitOnError)
+	return &ffcli.Command{
+		Name:       "cache",
+		ShortUsage: "fr24 cache <subcommand>",
+		ShortHelp:  "inspect and manage the on-disk cache",
+		FlagSet:    fs,
+		Exec: func(context.Context, []string) error {
+			return flag.ErrHelp
+		},
+		Subcommands: []*ffcli.Command{
+			cmdCacheLs(),
+			cmdCacheStats(),
+			cmdCachePrune(),
+			cmdCacheRm(),
+			cmdCacheExport(),
+		},
+	}
+}
+
+// cacheDirFlag registers -dir and returns a function opening the cache.
+func cacheDirFlag(fs *flag.FlagSet) func() (*lib.FR24Cache, error) {
+	dir := fs.String("dir", "", "cache directory (default user cache dir)")
+	return func() (*lib.FR24Cache, error) {
+		if *dir != "" {
+			return lib.NewCache(*dir), nil
+		}
+		return lib.DefaultCache()
+	}
+}
+
+func cmdCacheLs() *ffcli.Command {
+	fs := flag.NewFlagSet("ls", flag.ExitOnError)
+	open := cacheDirFlag(fs)
+	out := addOutputFlags(fs)
+	return &ffcli.Command{
+		Name:       "ls",
+		ShortUsage: "fr24 cache ls [flags] [kind]",
+		ShortHelp:  "list cache entries, optionally of one kind (e.g. live_feed)",
+		FlagSet:    fs,
+		Exec: func(ctx context.Context, args []string) error {
+			cache, err := open()
+			if err != nil {
+				return err
+			}
+			var kind string
+			if len(args) > 0 {
+				kind = args[0]
+			}
+			entries, err := cache.Entries(kind)
+			if err != nil {
+				return err
+			}
+			return out.write(entries)
+		},
+	}
+}
+
+func cmdCacheStats() *ffcli.Command {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	open := cacheDirFlag(fs)
+	out := addOutputFlags(fs)
+	return &ffcli.Command{
+		Name:       "stats",
+		ShortUsage: "fr24 cache stats [flags]",
+		ShortHelp:  "per-kind entry counts, bytes and oldest/newest write times",
+		FlagSet:    fs,
+		Exec: func(ctx context.Context, args []string) error {
+			cache, err := open()
+			if err != nil {
+				return err
+			}
+			stats, err := cache.Stats()
+			if err != nil {
+				return err
+			}
+			return out.write(stats)
+		},
+	}
+}
+
+func cmdCachePrune() *ffcli.Command {
+	fs := flag.NewFlagSet("prune", flag.ExitOnError)
+	open := cacheDirFlag(fs)
+	olderThan := fs.String("older-than", "", "remove entries written before this age, e.g. 30d, 12h")
+	maxSize := fs.String("max-size", "", "remove the oldest entries until the cache fits, e.g. 5GB")
+	dryRun := fs.Bool("dry-run", false, "only report what would be removed")
+	return &ffcli.Command{
+		Name:       "prune",
+		ShortUsage: "fr24 cache prune [flags]",
+		ShortHelp:  "remove old entries or shrink the cache to a size",
+		FlagSet:    fs,
+		Exec: func(ctx context.Context, args []string) error {
+			var opts lib.PruneOptions
+			var err error
+			if *olderThan != "" {
+				if opts.OlderThan, err = parseAge(*olderThan); err != nil {
+					return err
+				}
+			}
+			if *maxSize != "" {
+				if opts.MaxSize, err = parseSize(*maxSize); err != nil {
+					return err
+				}
+			}
+			if opts.OlderThan == 0 && opts.MaxSize == 0 {
+				return errors.New("missing -older-than or -max-size")
+			}
+			opts.DryRun = *dryRun
+			cache, err := open()
+			if err != nil {
+				return err
+			}
+			removed, err := cache.Prune(opts)
+			var bytes int64
+			for _, e := range removed {
+				bytes += e.Size
+			}
+			log.Printf("cache prune: %d entries, %d bytes", len(removed), bytes)
+			return err
+		},
+	}
+}
+
+func cmdCacheRm() *ffcli.Command {
+	fs := flag.NewFlagSet("rm", flag.ExitOnError)
+	open := cacheDirFlag(fs)
+	return &ffcli.Command{
+		Name:       "rm",
+		ShortUsage: "fr24 cache rm [flags] <kind> <key>",
+		ShortHelp:  "remove one entry, e.g. rm live_feed 1727740800",
+		FlagSet:    fs,
+		Exec: func(ctx context.Context, args []string) error {
+			if len(args) != 2 {
+				return errors.New("usage: fr24 cache rm <kind> <key>")
+			}
+			cache, err := open()
+			if err != nil {
+				return err
+			}
+			removed, err := cache.Remove(args[0], args[1])
+			if err != nil {
+				return err
+			}
+			for _, e := range removed {
+				log.Printf("cache rm: %s", e.Path)
+			}
+			return nil
+		},
+	}
+}
+
+func cmdCacheExport() *ffcli.Command {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	open := cacheDirFlag(fs)
+	kind := fs.String("kind", "", "kind to export, e.g. live_feed (default all)")
+	from := fs.String("from", "", "first timestamp (unix seconds or RFC 3339)")
+	to := fs.String("to", "", "last timestamp (unix seconds or RFC 3339)")
+	gz := fs.Bool("gzip", false, "gzip the tar stream (.tar.gz)")
+	zst := fs.Bool("zstd", false, "zstd-compress the tar stream (.tar.zst)")
+	return &ffcli.Command{
+		Name:       "export",
+		ShortUsage: "fr24 cache export [flags] > bundle.tar.zst",
+		ShortHelp:  "write entries as a tar stream to stdout, optionally gzip or zstd compressed",
+		FlagSet:    fs,
+		Exec: func(ctx context.Context, args []string) error {
+			var fromT, toT time.Time
+			if *from != "" {
+				ts, ok := lib.ToUnixSeconds(*from)
+				if !ok {
+					return fmt.Errorf("invalid -from %q", *from)
+				}
+				fromT = time.Unix(ts, 0)
+			}
+			if *to != "" {
+				ts, ok := lib.ToUnixSeconds(*to)
+				if !ok {
+					return fmt.Errorf("invalid -to %q", *to)
+				}
+				toT = time.Unix(ts, 0)
+			}
+			if *gz && *zst {
+				return errors.New("-gzip and -zstd are mutually exclusive")
+			}
+			cache, err := open()
+			if err != nil {
+				return err
+			}
+			var w io.WriteCloser = nopWriteCloser{os.Stdout}
+			switch {
+			case *gz:
+				w = gzip.NewWriter(os.Stdout)
+			case *zst:
+				if w, err = zstd.NewWriter(os.Stdout); err != nil {
+					return err
+				}
+			}
+			n, err := cache.Export(w, *kind, fromT, toT)
+			if err != nil {
+				_ = w.Close()
+				return err
+			}
+			// Close flushes the compressed stream; a failure here means a
+			// truncated archive.
+			if err := w.Close(); err != nil {
+				return err
+			}
+			log.Printf("cache export: %d files", n)
+			return nil
+		},
+	}
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+// parseAge parses a duration that may also use d (days) and w (weeks).
+func parseAge(s string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			v, err := strconv.ParseFloat(n, 64)
+			if err != nil || v < 0 {
+				return 0, fmt.Errorf("invalid age %q", s)
+			}
+			return time.Duration(v * float64(unit)), nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q", s)
+	}
+	return d, nil
+}
+
+// parseSize parses a byte size such as 500MB, 5GB or 2GiB. KB/MB/GB/TB are
+// powers of 1000 and KiB/MiB/GiB/TiB powers of 1024.
+func parseSize(s string) (int64, error) {
+	v := strings.ToUpper(strings.TrimSpace(s))
+	mult := 1.0
+	for _, u := range []struct {
+		suffix string
+		mult   float64
+	}{
+		{"KIB", 1 << 10}, {"MIB", 1 << 20}, {"GIB", 1 << 30}, {"TIB", 1 << 40},
+		{"KB", 1e3}, {"MB", 1e6}, {"GB", 1e9}, {"TB", 1e12}, {"B", 1},
+	} {
+		if n, ok := strings.CutSuffix(v, u.suffix); ok {
+			v, mult = n, u.mult
+			break
+		}
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return int64(f * mult), nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	lib "github.com/igolaizola/fr24/pkg/flightradar"
+)
+
+// clientFlags are the connection flags shared by every network subcommand.
+type clientFlags struct {
+	cacheDir *string
+	noCache  *bool
+	record   *string
+	replay   *string
+	retries  *int
+	retryMin *time.Duration
+	retryMax *time.Duration
+	rps      *float64
+	burst    *int
+	debug    *bool
+}
+
+func addClientFlags(fs *flag.FlagSet) *clientFlags {
+	return &clientFlags{
+		cacheDir: fs.String("cache", "", "cache directory for historic responses (default user cache dir)"),
+		noCache:  fs.Bool("no-cache", false, "always fetch historic data from the network"),
+		record:   fs.String("record", "", "record HTTP interactions to this cassette directory"),
+		replay:   fs.String("replay", "", "answer HTTP requests from this cassette directory instead of the network"),
+		retries:  fs.Int("max-retries", lib.DefaultRetryPolicy.MaxAttempts-1, "retries of transient failures for unary RPCs and JSON GETs (0 disables)"),
+		retryMin: fs.Duration("retry-wait", lib.DefaultRetryPolicy.BaseDelay, "backoff before the first retry, doubled on each retry"),
+		retryMax: fs.Duration("retry-max-wait", lib.DefaultRetryPolicy.MaxDelay, "maximum backoff and Retry-After honoured"),
+		rps:      fs.Float64("rps", 0, "requests per second for gRPC and JSON API calls each (0 uses config or defaults, negative disables)"),
+		burst:    fs.Int("burst", 0, "requests allowed at once before -rps applies (0 uses config or defaults)"),
+		debug:    fs.Bool("debug", false, "log diagnostics such as rate limit waits to stderr"),
+	}
+}
+
+// newClient returns a client using the endpoints and credentials from
+// env/config (falling back to anonymous access), retries and rate limits,
+// with read-through caching unless disabled.
+func (f *clientFlags) newClient() (*lib.Client, error) {
+	c, _, err := f.open()
+	return c, err
+}
+
+// open is newClient but also returns the login error, which newClient
+// ignores to fall back to anonymous access.
+func (f *clientFlags) open() (c *lib.Client, loginErr, err error) {
+	c = lib.New().WithEndpoints(lib.EndpointsFromEnvOrConfig())
+	var rt http.RoundTripper
+	switch {
+	case *f.record != "" && *f.replay != "":
+		return nil, nil, errors.New("-record and -replay are mutually exclusive")
+	case *f.record != "":
+		if rt, err = lib.NewRecordingTransport(*f.record); err != nil {
+			return nil, nil, err
+		}
+	case *f.replay != "":
+		if rt, err = lib.NewReplayTransport(*f.replay); err != nil {
+			return nil, nil, err
+		}
+	}
+	if rt != nil {
+		c.WithHTTP(&http.Client{Timeout: 20 * time.Second, Transport: rt})
+	}
+	c.WithRetry(lib.RetryPolicy{MaxAttempts: *f.retries + 1, BaseDelay: *f.retryMin, MaxDelay: *f.retryMax})
+	if *f.replay == "" {
+		c.WithRateLimits(f.rateLimits())
+	}
+	if *f.debug {
+		c.WithDebugLog(log.Default())
+	}
+	loginErr = c.LoginFromEnvOrConfig()
+	if !*f.noCache {
+		cache := lib.NewCache(*f.cacheDir)
+		if *f.cacheDir == "" {
+			if cache, err = lib.DefaultCache(); err != nil {
+				return nil, nil, err
+			}
+		}
+		c.WithCache(cache)
+	}
+	return c, loginErr, nil
+}
+
+// rateLimits returns the budgets from env/config and the built-in defaults,
+// with -rps and -burst overriding the gRPC and JSON API families.
+func (f *clientFlags) rateLimits() lib.RateLimits {
+	l := lib.RateLimitsFromEnvOrConfig(lib.DefaultRateLimits)
+	if *f.rps != 0 {
+		l.GRPC.RPS, l.API.RPS = *f.rps, *f.rps
+	}
+	if *f.burst > 0 {
+		l.GRPC.Burst, l.API.Burst = *f.burst, *f.burst
+	}
+	return l
+}
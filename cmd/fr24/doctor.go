@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"time"
+
+	lib "github.com/igolaizola/fr24/pkg/flightradar"
+	"github.com/peterbourgon/ff/v3/ffcli"
+)
+
+// doctorCheck is one line of the doctor report.
+type doctorCheck struct {
+	Check     string `json:"check"`
+	Status    string `json:"status"` // ok, warn or fail
+	LatencyMS int64  `json:"latency_ms"`
+	Detail    string `json:"detail"`
+}
+
+func cmdDoctor() *ffcli.Command {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	cf := addClientFlags(fs)
+	out := addOutputFlags(fs)
+	ticks := fs.Uint("ticks", 3, "ticks to request from the CountDown stream")
+	timeout := fs.Duration("timeout", 30*time.Second, "timeout for each check")
+	return &ffcli.Command{
+		Name:       "doctor",
+		ShortUsage: "fr24 doctor [flags]",
+		ShortHelp:  "check reachability, auth and server streaming",
+		FlagSet:    fs,
+		Exec: func(ctx context.Context, args []string) error {
+			c, loginErr, err := cf.open()
+			if err != nil {
+				return err
+			}
+			var checks []doctorCheck
+			add := func(name string, latency time.Duration, err error, detail string) {
+				ch := doctorCheck{Check: name, Status: "ok", LatencyMS: latency.Milliseconds(), Detail: detail}
+				if err != nil {
+					ch.Status, ch.Detail = "fail", err.Error()
+				}
+				checks = append(checks, ch)
+			}
+
+			add("auth", 0, loginErr, c.AuthMode())
+
+			// Ping times a single attempt, so a flaky endpoint fails here
+			// rather than passing after retries.
+			cctx, cancel := context.WithTimeout(ctx, *timeout)
+			pong, err := c.Ping(cctx)
+			cancel()
+			add("echo", pong.Latency, err, fmt.Sprintf("pong %d from %s", pong.Pong, c.Endpoints().GRPC))
+
+			cctx, cancel = context.WithTimeout(ctx, *timeout)
+			start := time.Now()
+			checks = append(checks, countDownCheck(cctx, c, uint32(*ticks), start))
+			cancel()
+
+			cctx, cancel = context.WithTimeout(ctx, *timeout)
+			start = time.Now()
+			err = findCheck(cctx, c)
+			cancel()
+			add("json-api", time.Since(start), err, "find on "+c.Endpoints().Web)
+
+			if err := out.write(checks); err != nil {
+				return err
+			}
+			for _, ch := range checks {
+				if ch.Status == "fail" {
+					return errors.New("doctor: some checks failed")
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// countDownCheck reads the CountDown stream. Ticks that all arrive at once
+// usually mean a proxy buffers the response, which breaks followflight.
+func countDownCheck(ctx context.Context, c *lib.Client, n uint32, start time.Time) doctorCheck {
+	ch := doctorCheck{Check: "stream", Status: "ok"}
+	var first, last time.Duration
+	count := 0
+	for _, err := range c.CountDown(ctx, n) {
+		if err != nil {
+			ch.Status, ch.Detail = "fail", err.Error()
+			ch.LatencyMS = time.Since(start).Milliseconds()
+			return ch
+		}
+		last = time.Since(start)
+		if count == 0 {
+			first = last
+		}
+		count++
+	}
+	ch.LatencyMS = time.Since(start).Milliseconds()
+	switch {
+	case count == 0:
+		ch.Status, ch.Detail = "fail", "stream ended without ticks"
+	case count > 1 && last-first < 50*time.Millisecond:
+		ch.Status = "warn"
+		ch.Detail = fmt.Sprintf("%d ticks arrived together after %s; a proxy may be buffering streams", count, first.Round(time.Millisecond))
+	default:
+		ch.Detail = fmt.Sprintf("%d ticks, first after %s, last after %s", count, first.Round(time.Millisecond), last.Round(time.Millisecond))
+	}
+	return ch
+}
+
+// findCheck makes a small JSON API request.
+func findCheck(ctx context.Context, c *lib.Client) error {
+	resp, err := c.Find(ctx, lib.FindParams{Query: "HKG", Limit: 1})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	_, err = readBody(resp)
+	return err
+}
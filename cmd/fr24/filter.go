@@ -0,0 +1,126 @@
+package main
+
+import (
+	"flag"
+	"strings"
+
+	lib "github.com/igolaizola/fr24/pkg/flightradar"
+)
+
+// listFlag collects comma-separated values; the flag may be repeated.
+type listFlag []string
+
+func (l *listFlag) String() string { return strings.Join(*l, ",") }
+func (l *listFlag) Set(v string) error {
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			*l = append(*l, s)
+		}
+	}
+	return nil
+}
+
+// liveFeedFlags registers bounding box, visibility and filter flags shared by
+// livefeed and playbackfeed, and returns a function that builds the params
+// after parsing.
+func liveFeedFlags(fs *flag.FlagSet) func() (lib.LiveFeedParams, error) {
+	south := fs.Float64("south", 42, "south")
+	north := fs.Float64("north", 52, "north")
+	west := fs.Float64("west", -8, "west")
+	east := fs.Float64("east", 10, "east")
+	sources := fs.String("sources", "", "data sources, e.g. adsb,mlat (default all)")
+	categories := fs.String("categories", "", "visible service categories, e.g. cargo,military (default all)")
+	traffic := fs.String("traffic", "all", "traffic type: all, airborne or ground")
+	filter := filterFlags(fs)
+	return func() (lib.LiveFeedParams, error) {
+		p := lib.LiveFeedParams{BoundingBox: lib.BoundingBox{South: float32(*south), North: float32(*north), West: float32(*west), East: float32(*east)}}
+		var err error
+		if p.Sources, err = lib.ParseDataSources(*sources); err != nil {
+			return p, err
+		}
+		if p.Services, err = lib.ParseServices(*categories); err != nil {
+			return p, err
+		}
+		if p.Traffic, err = lib.ParseTrafficType(*traffic); err != nil {
+			return p, err
+		}
+		if p.Filter, err = filter(); err != nil {
+			return p, err
+		}
+		return p, nil
+	}
+}
+
+// filterFlags registers the live feed filter flags on fs and returns a
+// function that builds and validates the filter after parsing.
+func filterFlags(fs *flag.FlagSet) func() (lib.LiveFeedFilter, error) {
+	var alt, speed, airline, callsign, radar, reg, airport, flt, typ, birth, squawk, origin, dest, category, airspace listFlag
+	fs.Var(&alt, "alt", "altitude range in feet, e.g. 10000-40000")
+	fs.Var(&speed, "speed", "ground speed range in knots, e.g. 200-500")
+	fs.Var(&airline, "airline", "airline ICAO, e.g. CPA or CPA:operated")
+	fs.Var(&callsign, "callsign", "callsign(s)")
+	fs.Var(&radar, "radar", "receiver id(s), e.g. T-VHST000")
+	fs.Var(&reg, "reg", "registration(s)")
+	fs.Var(&airport, "airport", "airport IATA, e.g. HKG, HKG:inbound, HKG:outbound")
+	fs.Var(&flt, "flight", "flight number(s)")
+	fs.Var(&typ, "type", "aircraft type(s), wildcards allowed, e.g. 'A35*'")
+	fs.Var(&birth, "birth-year", "aircraft birth year range, e.g. 2010-2020")
+	fs.Var(&squawk, "squawk", "squawk code(s), e.g. 7700")
+	fs.Var(&origin, "origin", "origin airport IATA")
+	fs.Var(&dest, "destination", "destination airport IATA")
+	fs.Var(&category, "category", "filter by service category, e.g. cargo, military")
+	fs.Var(&airspace, "airspace", "airspace id(s)")
+	return func() (lib.LiveFeedFilter, error) {
+		var f lib.LiveFeedFilter
+		var err error
+		if f.Altitudes, err = parseAll(alt, lib.ParseInterval); err != nil {
+			return f, err
+		}
+		if f.Speeds, err = parseAll(speed, lib.ParseInterval); err != nil {
+			return f, err
+		}
+		if f.BirthYears, err = parseAll(birth, lib.ParseInterval); err != nil {
+			return f, err
+		}
+		if f.Airlines, err = parseAll(airline, lib.ParseAirlineFilter); err != nil {
+			return f, err
+		}
+		if f.Airports, err = parseAll(airport, lib.ParseAirportFilter); err != nil {
+			return f, err
+		}
+		if f.Origins, err = parseAll(origin, lib.ParseAirportFilter); err != nil {
+			return f, err
+		}
+		if f.Destinations, err = parseAll(dest, lib.ParseAirportFilter); err != nil {
+			return f, err
+		}
+		if f.Squawks, err = parseAll(squawk, lib.ParseSquawk); err != nil {
+			return f, err
+		}
+		if f.Categories, err = parseAll(category, lib.ParseService); err != nil {
+			return f, err
+		}
+		f.Callsigns, f.Radars, f.Regs, f.Flights, f.Types, f.Airspaces = upper(callsign), radar, upper(reg), upper(flt), upper(typ), airspace
+		return f, f.Validate()
+	}
+}
+
+func parseAll[T any](vals []string, parse func(string) (T, error)) ([]T, error) {
+	var out []T
+	for _, v := range vals {
+		t, err := parse(v)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+func upper(vals []string) []string {
+	var out []string
+	for _, v := range vals {
+		out = append(out, strings.ToUpper(v))
+	}
+	return out
+}
@@ -3,7 +3,6 @@ package main
 import (
     "compress/gzip"
     "context"
-    "encoding/json"
     "errors"
     "flag"
     "fmt"
@@ -17,6 +16,7 @@ import (
     "time"
 
     lib "github.com/igolaizola/fr24/pkg/flightradar"
+    pb "github.com/igolaizola/fr24/pkg/proto"
     "github.com/peterbourgon/ff/v3"
     "github.com/peterbourgon/ff/v3/ffcli"
     "github.com/peterbourgon/ff/v3/ffyaml"
@@ -50,15 +50,21 @@ func newCommand() *ffcli.Command {
             newVersionCommand(),
             cmdLogin(),
             cmdDirs(),
+            cmdDoctor(),
+            cmdCache(),
             cmdFlightList(),
             cmdAirportList(),
             cmdFind(),
             cmdLiveFeed(),
             cmdPlaybackFeed(),
+            cmdPlayback(),
             cmdNearest(),
             cmdLiveStatus(),
             cmdTopFlights(),
             cmdFlightDetails(),
+            cmdSearchIndex(),
+            cmdLiveTrail(),
+            cmdHistoricTrail(),
             cmdPlaybackFlight(),
             cmdFollowFlight(),
         },
@@ -101,7 +107,7 @@ func cmdLogin() *ffcli.Command {
         ShortHelp:  "authenticate using env/config",
         FlagSet:    fs,
         Exec: func(ctx context.Context, args []string) error {
-            c := lib.New()
+            c := lib.New().WithEndpoints(lib.EndpointsFromEnvOrConfig())
             if err := c.LoginFromEnvOrConfig(); err != nil {
                 return err
             }
@@ -117,6 +123,7 @@ func cmdLogin() *ffcli.Command {
 
 func cmdDirs() *ffcli.Command {
     fs := flag.NewFlagSet("dirs", flag.ExitOnError)
+    out := addOutputFlags(fs)
     return &ffcli.Command{
         Name:       "dirs",
         ShortUsage: "fr24 dirs",
@@ -127,26 +134,36 @@ func cmdDirs() *ffcli.Command {
             if err != nil {
                 return err
             }
-            b, _ := json.MarshalIndent(map[string]string{"base": cache.Base()}, "", "  ")
-            _, _ = os.Stdout.Write(b)
-            return nil
+            return out.write(map[string]string{"base": cache.Base()})
         },
     }
 }
 
 func cmdFlightList() *ffcli.Command {
     fs := flag.NewFlagSet("flightlist", flag.ExitOnError)
+    cf := addClientFlags(fs)
+    out := addOutputFlags(fs)
     reg := fs.String("reg", "", "registration")
     flt := fs.String("flight", "", "flight number")
+    pf := addPageFlags(fs)
     return &ffcli.Command{
         Name:       "flightlist",
         ShortUsage: "fr24 flightlist [flags]",
         ShortHelp:  "list flights by registration or number",
         FlagSet:    fs,
         Exec: func(ctx context.Context, args []string) error {
-            c := lib.New()
-            _ = c.LoginFromEnvOrConfig()
-            resp, err := c.FlightList(ctx, lib.FlightListParams{Reg: *reg, Flight: *flt, Page: 1, Limit: 10})
+            c, err := cf.newClient()
+            if err != nil {
+                return err
+            }
+            p := lib.FlightListParams{Reg: *reg, Flight: *flt, Page: 1, Limit: *pf.limit}
+            if p.TimestampS, err = pf.before(); err != nil {
+                return err
+            }
+            if *pf.all {
+                return writeAll(out, c.FlightListAll(ctx, p, *pf.maxPages))
+            }
+            resp, err := c.FlightList(ctx, p)
             if err != nil {
                 return err
             }
@@ -156,129 +173,248 @@ func cmdFlightList() *ffcli.Command {
             if err != nil {
                 return err
             }
-            enc := json.NewEncoder(os.Stdout)
-            enc.SetIndent("", "  ")
-            return enc.Encode(recs)
+            return out.write(recs)
         },
     }
 }
 
 func cmdAirportList() *ffcli.Command {
     fs := flag.NewFlagSet("airportlist", flag.ExitOnError)
+    cf := addClientFlags(fs)
+    out := addOutputFlags(fs)
     code := fs.String("code", "HKG", "IATA code")
     mode := fs.String("mode", "arrivals", "arrivals|departures|ground")
+    pf := addPageFlags(fs)
     return &ffcli.Command{
         Name:       "airportlist",
         ShortUsage: "fr24 airportlist [flags]",
         ShortHelp:  "airport schedule list",
         FlagSet:    fs,
         Exec: func(ctx context.Context, args []string) error {
-            c := lib.New()
-            _ = c.LoginFromEnvOrConfig()
-            resp, err := c.AirportList(ctx, lib.AirportListParams{Airport: *code, Mode: lib.AirportMode(*mode), Page: 1, Limit: 10})
+            c, err := cf.newClient()
             if err != nil {
                 return err
             }
-            defer func() { _ = resp.Body.Close() }()
-            _, err = io.Copy(os.Stdout, mustReadBodyReader(resp))
-            return err
+            p := lib.AirportListParams{Airport: *code, Mode: lib.AirportMode(*mode), Page: 1, Limit: *pf.limit}
+            if p.TimestampS, err = pf.before(); err != nil {
+                return err
+            }
+            if *pf.all {
+                return writeAll(out, c.AirportListAll(ctx, p, *pf.maxPages))
+            }
+            resp, err := c.AirportList(ctx, p)
+            if err != nil {
+                return err
+            }
+            body, err := readBody(resp)
+            if err != nil {
+                return err
+            }
+            recs, err := lib.ParseAirportList(body)
+            if err != nil {
+                return err
+            }
+            return out.write(recs)
         },
     }
 }
 
 func cmdFind() *ffcli.Command {
     fs := flag.NewFlagSet("find", flag.ExitOnError)
+    cf := addClientFlags(fs)
+    out := addOutputFlags(fs)
     q := fs.String("q", "A359", "query")
+    kind := choiceFlag{choices: []string{lib.SearchAirport, lib.SearchOperator, lib.SearchAircraft, lib.SearchLive, lib.SearchSchedule}}
+    fs.Var(&kind, "type", "only output results of one kind with typed columns: airport, operator, aircraft, live or schedule")
     return &ffcli.Command{
         Name:       "find",
         ShortUsage: "fr24 find [flags]",
         ShortHelp:  "search entities",
         FlagSet:    fs,
         Exec: func(ctx context.Context, args []string) error {
-            c := lib.New()
-            _ = c.LoginFromEnvOrConfig()
+            c, err := cf.newClient()
+            if err != nil {
+                return err
+            }
             resp, err := c.Find(ctx, lib.FindParams{Query: *q, Limit: 50})
             if err != nil {
                 return err
             }
-            defer func() { _ = resp.Body.Close() }()
-            _, err = io.Copy(os.Stdout, mustReadBodyReader(resp))
-            return err
+            body, err := readBody(resp)
+            if err != nil {
+                return err
+            }
+            res, err := lib.ParseFind(body)
+            if err != nil {
+                return err
+            }
+            switch kind.value {
+            case lib.SearchAirport:
+                return out.write(res.Airports)
+            case lib.SearchOperator:
+                return out.write(res.Operators)
+            case lib.SearchAircraft:
+                return out.write(res.Aircraft)
+            case lib.SearchLive:
+                return out.write(res.Live)
+            case lib.SearchSchedule:
+                return out.write(res.Schedule)
+            }
+            return out.write(res.All)
         },
     }
 }
 
 func cmdLiveFeed() *ffcli.Command {
     fs := flag.NewFlagSet("livefeed", flag.ExitOnError)
-    south := fs.Float64("south", 42, "south")
-    north := fs.Float64("north", 52, "north")
-    west := fs.Float64("west", -8, "west")
-    east := fs.Float64("east", 10, "east")
+    cf := addClientFlags(fs)
+    out := addOutputFlags(fs)
+    params := liveFeedFlags(fs)
+    compress := fs.Bool("compress", false, "request gzip-compressed responses")
+    world := fs.Bool("world", false, "scan the whole world in tiles (ignores bounding box)")
+    tiled := fs.Bool("tiled", false, "scan the bounding box in tiles to beat the per-request limit")
+    concurrency := fs.Int("concurrency", 4, "parallel requests for tiled scans")
     return &ffcli.Command{
         Name:       "livefeed",
         ShortUsage: "fr24 livefeed [flags]",
         ShortHelp:  "live feed in bounding box",
         FlagSet:    fs,
         Exec: func(ctx context.Context, args []string) error {
-            c := lib.New()
-            _ = c.LoginFromEnvOrConfig()
-            p := lib.LiveFeedParams{BoundingBox: lib.BoundingBox{South: float32(*south), North: float32(*north), West: float32(*west), East: float32(*east)}}
-            resp, err := c.GrpcLiveFeed(ctx, p)
+            c, err := cf.newClient()
             if err != nil {
                 return err
             }
-            defer func() { _ = resp.Body.Close() }()
-            b, _ := io.ReadAll(resp.Body)
-            msg, err := lib.ParseLiveFeedGRPC(b)
+            p, err := params()
             if err != nil {
                 return err
             }
-            out := make([]lib.LiveFeedFlightRecord, 0, len(msg.GetFlightsList()))
-            for _, f := range msg.GetFlightsList() {
-                out = append(out, lib.LiveFeedFlightToRecord(f))
+            if *compress {
+                c.WithGrpcAcceptEncoding("gzip")
+            }
+            var flights []*pb.Flight
+            if *world || *tiled {
+                bb := p.BoundingBox
+                if *world {
+                    bb = lib.WorldBoundingBox
+                }
+                scanner := lib.NewLiveFeedScanner(c, p)
+                scanner.Concurrency = *concurrency
+                res, err := scanner.Scan(ctx, bb)
+                if err != nil {
+                    return err
+                }
+                if res.Truncated > 0 {
+                    log.Printf("livefeed: %d tiles still at the flight limit; snapshot may be incomplete", res.Truncated)
+                }
+                flights = res.Flights
+            } else {
+                msg, err := c.LiveFeed(ctx, p)
+                if err != nil {
+                    return err
+                }
+                flights = msg.GetFlightsList()
+            }
+            recs := make([]lib.LiveFeedFlightRecord, 0, len(flights))
+            for _, f := range flights {
+                recs = append(recs, lib.LiveFeedFlightToRecord(f))
             }
-            return json.NewEncoder(os.Stdout).Encode(out)
+            return out.write(recs)
         },
     }
 }
 
 func cmdPlaybackFeed() *ffcli.Command {
     fs := flag.NewFlagSet("playbackfeed", flag.ExitOnError)
-    south := fs.Float64("south", 42, "south")
-    north := fs.Float64("north", 52, "north")
-    west := fs.Float64("west", -8, "west")
-    east := fs.Float64("east", 10, "east")
+    cf := addClientFlags(fs)
+    out := addOutputFlags(fs)
+    params := liveFeedFlags(fs)
+    compress := fs.Bool("compress", false, "request gzip-compressed responses")
     dur := fs.Int("duration", 7, "duration seconds")
+    from := fs.String("from", "", "range start (unix seconds or RFC 3339, e.g. 2026-10-01T00:00Z)")
+    to := fs.String("to", "", "range end (unix seconds or RFC 3339)")
+    step := fs.Duration("step", time.Minute, "step between range snapshots")
+    outDir := fs.String("out", "", "output directory for range snapshots (one file per timestamp)")
+    outFormat := fs.String("out-format", "csv", "range snapshot file format: csv or parquet")
+    tiled := fs.Bool("tiled", false, "fetch each snapshot in tiles to beat the per-request limit")
+    concurrency := fs.Int("concurrency", 4, "parallel requests for tiled snapshots")
     return &ffcli.Command{
         Name:       "playbackfeed",
         ShortUsage: "fr24 playbackfeed [flags]",
         ShortHelp:  "historical live feed snapshot",
         FlagSet:    fs,
         Exec: func(ctx context.Context, args []string) error {
-            c := lib.New()
-            _ = c.LoginFromEnvOrConfig()
-            p := lib.LiveFeedPlaybackParams{LiveFeed: lib.LiveFeedParams{BoundingBox: lib.BoundingBox{South: float32(*south), North: float32(*north), West: float32(*west), East: float32(*east)}}, Duration: int32(*dur)}
-            resp, err := c.GrpcPlayback(ctx, p)
+            c, err := cf.newClient()
             if err != nil {
                 return err
             }
-            defer func() { _ = resp.Body.Close() }()
-            b, _ := io.ReadAll(resp.Body)
-            msg, err := lib.ParsePlaybackGRPC(b)
+            p, err := params()
+            if err != nil {
+                return err
+            }
+            if *compress {
+                c.WithGrpcAcceptEncoding("gzip")
+            }
+            if *from != "" || *to != "" {
+                format, err := lib.ParseFileFormat(*outFormat)
+                if err != nil {
+                    return err
+                }
+                return playbackRange(ctx, c, p, *from, *to, *step, int32(*dur), *outDir, format, *tiled, *concurrency)
+            }
+            msg, err := c.PlaybackFeed(ctx, lib.LiveFeedPlaybackParams{LiveFeed: p, Duration: int32(*dur)})
             if err != nil {
                 return err
             }
-            out := make([]lib.LiveFeedFlightRecord, 0, len(msg.GetLiveFeedResponse().GetFlightsList()))
+            recs := make([]lib.LiveFeedFlightRecord, 0, len(msg.GetLiveFeedResponse().GetFlightsList()))
             for _, f := range msg.GetLiveFeedResponse().GetFlightsList() {
-                out = append(out, lib.LiveFeedFlightToRecord(f))
+                recs = append(recs, lib.LiveFeedFlightToRecord(f))
             }
-            return json.NewEncoder(os.Stdout).Encode(out)
+            return out.write(recs)
         },
     }
 }
 
+// playbackRange archives snapshots between from and to into outDir, skipping
+// timestamps already on disk so interrupted runs can be resumed.
+func playbackRange(ctx context.Context, c *lib.Client, p lib.LiveFeedParams, from, to string, step time.Duration, dur int32, outDir string, format lib.FileFormat, tiled bool, concurrency int) error {
+    if outDir == "" {
+        return errors.New("missing -out")
+    }
+    fromTS, ok := lib.ToUnixSeconds(from)
+    if !ok {
+        return fmt.Errorf("invalid -from %q", from)
+    }
+    toTS, ok := lib.ToUnixSeconds(to)
+    if !ok {
+        return fmt.Errorf("invalid -to %q", to)
+    }
+    cache := lib.NewCache(outDir).WithFormat(format)
+    rp := lib.PlaybackRangeParams{
+        LiveFeed:    p,
+        From:        time.Unix(fromTS, 0),
+        To:          time.Unix(toTS, 0),
+        Step:        step,
+        Duration:    dur,
+        Tiled:       tiled,
+        Concurrency: concurrency,
+        Skip:        cache.LiveFeedExists,
+    }
+    for snap, err := range c.PlaybackRange(ctx, rp) {
+        if err != nil {
+            return err
+        }
+        if err := cache.SaveLiveFeed(snap.Timestamp, snap.Records()); err != nil {
+            return err
+        }
+        log.Printf("playbackfeed: %d flights -> %s", len(snap.Flights), cache.LiveFeedPath(snap.Timestamp))
+    }
+    return nil
+}
+
 func cmdNearest() *ffcli.Command {
     fs := flag.NewFlagSet("nearest", flag.ExitOnError)
+    cf := addClientFlags(fs)
+    out := addOutputFlags(fs)
     lat := fs.Float64("lat", 22.3, "lat")
     lon := fs.Float64("lon", 114.2, "lon")
     return &ffcli.Command{
@@ -287,25 +423,23 @@ func cmdNearest() *ffcli.Command {
         ShortHelp:  "nearest flights to a location",
         FlagSet:    fs,
         Exec: func(ctx context.Context, args []string) error {
-            c := lib.New()
-            _ = c.LoginFromEnvOrConfig()
-            resp, err := c.GrpcNearestFlights(ctx, lib.NearestFlightsParams{Lat: float32(*lat), Lon: float32(*lon)})
+            c, err := cf.newClient()
             if err != nil {
                 return err
             }
-            defer func() { _ = resp.Body.Close() }()
-            b, _ := io.ReadAll(resp.Body)
-            msg, err := lib.ParseNearestFlightsGRPC(b)
+            msg, err := c.NearestFlights(ctx, lib.NearestFlightsParams{Lat: float32(*lat), Lon: float32(*lon)})
             if err != nil {
                 return err
             }
-            return json.NewEncoder(os.Stdout).Encode(lib.NearbyToRecords(msg))
+            return out.write(lib.NearbyToRecords(msg))
         },
     }
 }
 
 func cmdLiveStatus() *ffcli.Command {
     fs := flag.NewFlagSet("livestatus", flag.ExitOnError)
+    cf := addClientFlags(fs)
+    out := addOutputFlags(fs)
     id := fs.Uint("id", 0, "flight id")
     return &ffcli.Command{
         Name:       "livestatus",
@@ -316,25 +450,23 @@ func cmdLiveStatus() *ffcli.Command {
             if *id == 0 {
                 return errors.New("missing -id")
             }
-            c := lib.New()
-            _ = c.LoginFromEnvOrConfig()
-            resp, err := c.GrpcLiveFlightsStatus(ctx, lib.LiveFlightsStatusParams{FlightIDs: []uint32{uint32(*id)}})
+            c, err := cf.newClient()
             if err != nil {
                 return err
             }
-            defer func() { _ = resp.Body.Close() }()
-            b, _ := io.ReadAll(resp.Body)
-            msg, err := lib.ParseLiveFlightsStatusGRPC(b)
+            msg, err := c.LiveFlightsStatus(ctx, lib.LiveFlightsStatusParams{FlightIDs: []uint32{uint32(*id)}})
             if err != nil {
                 return err
             }
-            return json.NewEncoder(os.Stdout).Encode(lib.LiveFlightsStatusToRecords(msg))
+            return out.write(lib.LiveFlightsStatusToRecords(msg))
         },
     }
 }
 
 func cmdTopFlights() *ffcli.Command {
     fs := flag.NewFlagSet("topflights", flag.ExitOnError)
+    cf := addClientFlags(fs)
+    out := addOutputFlags(fs)
     limit := fs.Int("limit", 10, "limit 1-10")
     return &ffcli.Command{
         Name:       "topflights",
@@ -342,29 +474,27 @@ func cmdTopFlights() *ffcli.Command {
         ShortHelp:  "most viewed flights",
         FlagSet:    fs,
         Exec: func(ctx context.Context, args []string) error {
-            c := lib.New()
-            _ = c.LoginFromEnvOrConfig()
-            resp, err := c.GrpcTopFlights(ctx, lib.TopFlightsParams{Limit: int32(*limit)})
+            c, err := cf.newClient()
             if err != nil {
                 return err
             }
-            defer func() { _ = resp.Body.Close() }()
-            body, _ := io.ReadAll(resp.Body)
-            tf, err := lib.ParseTopFlightsGRPC(body)
+            tf, err := c.TopFlights(ctx, lib.TopFlightsParams{Limit: int32(*limit)})
             if err != nil {
                 return err
             }
-            var out []lib.TopFlightRecord
+            var recs []lib.TopFlightRecord
             for _, ff := range tf.GetScoreboardList() {
-                out = append(out, lib.TopFlightToRecord(ff))
+                recs = append(recs, lib.TopFlightToRecord(ff))
             }
-            return json.NewEncoder(os.Stdout).Encode(out)
+            return out.write(recs)
         },
     }
 }
 
 func cmdFlightDetails() *ffcli.Command {
     fs := flag.NewFlagSet("flightdetails", flag.ExitOnError)
+    cf := addClientFlags(fs)
+    out := addOutputFlags(fs)
     id := fs.Uint("id", 0, "flight id")
     return &ffcli.Command{
         Name:       "flightdetails",
@@ -375,25 +505,157 @@ func cmdFlightDetails() *ffcli.Command {
             if *id == 0 {
                 return errors.New("missing -id")
             }
-            c := lib.New()
-            _ = c.LoginFromEnvOrConfig()
-            resp, err := c.GrpcFlightDetails(ctx, lib.FlightDetailsParams{FlightID: uint32(*id)})
+            c, err := cf.newClient()
             if err != nil {
                 return err
             }
-            defer func() { _ = resp.Body.Close() }()
-            b, _ := io.ReadAll(resp.Body)
-            msg, err := lib.ParseFlightDetailsGRPC(b)
+            msg, err := c.FlightDetails(ctx, lib.FlightDetailsParams{FlightID: uint32(*id)})
+            if err != nil {
+                return err
+            }
+            return out.write(lib.FlightDetailsToRecord(msg))
+        },
+    }
+}
+
+func cmdSearchIndex() *ffcli.Command {
+    fs := flag.NewFlagSet("searchindex", flag.ExitOnError)
+    cf := addClientFlags(fs)
+    out := addOutputFlags(fs)
+    var f lib.SearchIndexFilter
+    fs.Var((*listFlag)(&f.Operators), "operator", "operator ICAO, e.g. CPA")
+    fs.Var((*listFlag)(&f.Types), "type", "aircraft type, wildcards allowed, e.g. 'A35*'")
+    fs.Var((*listFlag)(&f.Origins), "from", "scheduled origin IATA, e.g. HKG")
+    fs.Var((*listFlag)(&f.Destinations), "to", "scheduled destination IATA")
+    fs.Var((*listFlag)(&f.Callsigns), "callsign", "callsign, e.g. CPA255")
+    fs.Var((*listFlag)(&f.Registrations), "reg", "registration, e.g. B-LRA")
+    fs.Var((*listFlag)(&f.Flights), "flight", "flight number, e.g. CX255")
+    return &ffcli.Command{
+        Name:       "searchindex",
+        ShortUsage: "fr24 searchindex [flags]",
+        ShortHelp:  "index of every live flight, filtered locally",
+        FlagSet:    fs,
+        Exec: func(ctx context.Context, args []string) error {
+            c, err := cf.newClient()
+            if err != nil {
+                return err
+            }
+            msg, err := c.FetchSearchIndex(ctx)
             if err != nil {
                 return err
             }
-            return json.NewEncoder(os.Stdout).Encode(lib.FlightDetailsToRecord(msg))
+            return out.write(f.Filter(lib.SearchIndexToRecords(msg)))
+        },
+    }
+}
+
+func cmdLiveTrail() *ffcli.Command {
+    fs := flag.NewFlagSet("livetrail", flag.ExitOnError)
+    cf := addClientFlags(fs)
+    out := addOutputFlags(fs)
+    id := fs.Uint("id", 0, "flight id")
+    return &ffcli.Command{
+        Name:       "livetrail",
+        ShortUsage: "fr24 livetrail [flags]",
+        ShortHelp:  "full trail of a live flight",
+        FlagSet:    fs,
+        Exec: func(ctx context.Context, args []string) error {
+            if *id == 0 {
+                return errors.New("missing -id")
+            }
+            c, err := cf.newClient()
+            if err != nil {
+                return err
+            }
+            msg, err := c.LiveTrail(ctx, uint32(*id))
+            if err != nil {
+                return err
+            }
+            return out.write(lib.TrailToRecords(msg.GetRadarRecordsList()))
+        },
+    }
+}
+
+func cmdHistoricTrail() *ffcli.Command {
+    fs := flag.NewFlagSet("historictrail", flag.ExitOnError)
+    cf := addClientFlags(fs)
+    out := addOutputFlags(fs)
+    id := fs.Uint("id", 0, "flight id")
+    return &ffcli.Command{
+        Name:       "historictrail",
+        ShortUsage: "fr24 historictrail [flags]",
+        ShortHelp:  "full trail of a past flight",
+        FlagSet:    fs,
+        Exec: func(ctx context.Context, args []string) error {
+            if *id == 0 {
+                return errors.New("missing -id")
+            }
+            c, err := cf.newClient()
+            if err != nil {
+                return err
+            }
+            msg, err := c.HistoricTrail(ctx, uint32(*id))
+            if err != nil {
+                return err
+            }
+            return out.write(lib.TrailToRecords(msg.GetRadarRecordsList()))
+        },
+    }
+}
+
+func cmdPlayback() *ffcli.Command {
+    fs := flag.NewFlagSet("playback", flag.ExitOnError)
+    cf := addClientFlags(fs)
+    out := addOutputFlags(fs)
+    id := fs.String("id", "", "flight id (hex)")
+    ts := fs.String("ts", "", "departure time (unix seconds or RFC 3339; default now)")
+    track := fs.Bool("track", false, "output the track points instead of the flight metadata")
+    return &ffcli.Command{
+        Name:       "playback",
+        ShortUsage: "fr24 playback [flags]",
+        ShortHelp:  "metadata and track of a historic flight (JSON playback)",
+        FlagSet:    fs,
+        Exec: func(ctx context.Context, args []string) error {
+            if *id == "" {
+                return errors.New("missing -id")
+            }
+            p := lib.PlaybackParams{FlightIDHex: *id}
+            if *ts != "" {
+                t, ok := lib.ToUnixSeconds(*ts)
+                if !ok {
+                    return fmt.Errorf("invalid -ts %q", *ts)
+                }
+                p.TimestampS = &t
+            }
+            c, err := cf.newClient()
+            if err != nil {
+                return err
+            }
+            resp, err := c.Playback(ctx, p)
+            if err != nil {
+                return err
+            }
+            body, err := readBody(resp)
+            if err != nil {
+                return err
+            }
+            f, err := lib.ParsePlaybackFlight(body)
+            if err != nil {
+                return err
+            }
+            if *track {
+                return out.write(f.Track)
+            }
+            f.Track = nil
+            return out.write(f)
         },
     }
 }
 
 func cmdPlaybackFlight() *ffcli.Command {
     fs := flag.NewFlagSet("playbackflight", flag.ExitOnError)
+    cf := addClientFlags(fs)
+    out := addOutputFlags(fs)
     id := fs.Uint("id", 0, "flight id")
     ts := fs.Uint64("ts", uint64(time.Now().Unix()), "departure ts")
     return &ffcli.Command{
@@ -405,28 +667,27 @@ func cmdPlaybackFlight() *ffcli.Command {
             if *id == 0 {
                 return errors.New("missing -id")
             }
-            c := lib.New()
-            _ = c.LoginFromEnvOrConfig()
-            resp, err := c.GrpcPlaybackFlight(ctx, lib.PlaybackFlightParams{FlightID: uint32(*id), Timestamp: *ts})
+            c, err := cf.newClient()
             if err != nil {
                 return err
             }
-            defer func() { _ = resp.Body.Close() }()
-            b, _ := io.ReadAll(resp.Body)
-            msg, err := lib.ParsePlaybackFlightGRPC(b)
+            msg, err := c.PlaybackFlight(ctx, lib.PlaybackFlightParams{FlightID: uint32(*id), Timestamp: *ts})
             if err != nil {
                 return err
             }
-            return json.NewEncoder(os.Stdout).Encode(lib.PlaybackFlightToRecord(msg))
+            return out.write(lib.PlaybackFlightToRecord(msg))
         },
     }
 }
 
 func cmdFollowFlight() *ffcli.Command {
     fs := flag.NewFlagSet("followflight", flag.ExitOnError)
+    cf := addClientFlags(fs)
+    out := addOutputFlags(fs)
     id := fs.Uint("id", 0, "flight id")
     timeout := fs.Int("timeout", 0, "seconds to run (0=until Ctrl-C)")
     once := fs.Bool("once", false, "exit after first frame")
+    retries := fs.Int("retries", 0, "max consecutive reconnect attempts (0=unlimited)")
     return &ffcli.Command{
         Name:       "followflight",
         ShortUsage: "fr24 followflight [flags]",
@@ -441,53 +702,45 @@ func cmdFollowFlight() *ffcli.Command {
             if *id == 0 {
                 return errors.New("missing -id")
             }
-            c := lib.New()
-            _ = c.LoginFromEnvOrConfig()
+            c, err := cf.newClient()
+            if err != nil {
+                return err
+            }
             // Optional timeout for consistent tests
             if *timeout > 0 {
                 var cancelTimeout context.CancelFunc
                 ctx, cancelTimeout = context.WithTimeout(ctx, time.Duration(*timeout)*time.Second)
                 defer cancelTimeout()
             }
-            ch, cancel, err := c.GrpcFollowFlightStream(ctx, uint32(*id), 0)
-            if err != nil {
-                return err
-            }
-            defer cancel()
-            enc := json.NewEncoder(os.Stdout)
-            wrote := false
-            for frame := range ch {
-                if msg, err := lib.ParseLiveFeedGRPC(frame); err == nil {
-                    out := make([]lib.LiveFeedFlightRecord, 0, len(msg.GetFlightsList()))
-                    for _, f := range msg.GetFlightsList() {
-                        out = append(out, lib.LiveFeedFlightToRecord(f))
-                    }
-                    if err := enc.Encode(out); err != nil {
-                        return err
-                    }
-                    wrote = true
-                    if *once {
-                        break
-                    }
+            sub := c.SubscribeFollowFlight(ctx, uint32(*id), lib.FollowFlightOptions{MaxRetries: *retries})
+            defer sub.Close()
+            go func() {
+                for err := range sub.Errors {
+                    log.Printf("followflight: %v", err)
+                }
+            }()
+            st, err := out.stream()
+            if err != nil {
+                return err
+            }
+            defer func() { _ = st.close() }()
+            for msg := range sub.C {
+                if err := st.write(lib.FollowFlightToRecord(msg)); err != nil {
+                    return err
                 }
+                if *once {
+                    return nil
+                }
+            }
+            if err := sub.Err(); err != nil && !errors.Is(err, lib.ErrFlightLanded) {
+                return err
             }
-            _ = wrote
             return nil
         },
     }
 }
 
 // Helpers preserved from previous implementation
-func mustReadBodyReader(resp *http.Response) io.Reader {
-    if resp.Header.Get("Content-Encoding") == "gzip" {
-        zr, err := gzip.NewReader(resp.Body)
-        if err == nil {
-            return zr
-        }
-    }
-    return resp.Body
-}
-
 func readBody(resp *http.Response) ([]byte, error) {
     defer func() { _ = resp.Body.Close() }()
     if resp.Header.Get("Content-Encoding") == "gzip" {
@@ -0,0 +1,550 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	lib "github.com/igolaizola/fr24/pkg/flightradar"
+)
+
+// choiceFlag is a string flag restricted to a set of values.
+type choiceFlag struct {
+	value   string
+	choices []string
+}
+
+func (c *choiceFlag) String() string { return c.value }
+func (c *choiceFlag) Set(v string) error {
+	if !slices.Contains(c.choices, v) {
+		return fmt.Errorf("want one of %s", strings.Join(c.choices, ", "))
+	}
+	c.value = v
+	return nil
+}
+
+// outputFlags is the output layer shared by every data subcommand: the
+// format, an optional column selection and the destination.
+type outputFlags struct {
+	format choiceFlag
+	fields listFlag
+	path   *string
+}
+
+func addOutputFlags(fs *flag.FlagSet) *outputFlags {
+	o := &outputFlags{format: choiceFlag{value: "json", choices: []string{"json", "ndjson", "csv", "table", "parquet"}}}
+	fs.Var(&o.format, "format", "output format: json, ndjson, csv, table or parquet")
+	fs.Var(&o.fields, "fields", "comma-separated columns to output, e.g. flightid,callsign (nested as live.callsign)")
+	o.path = fs.String("o", "", "write output to file instead of stdout")
+	return o
+}
+
+// write writes v, a record or a slice of records, in the selected format.
+func (o *outputFlags) write(v any) error {
+	w, closeOut, err := o.open()
+	if err != nil {
+		return err
+	}
+	if err := o.encode(w, v); err != nil {
+		_ = closeOut()
+		return err
+	}
+	return closeOut()
+}
+
+func (o *outputFlags) open() (io.Writer, func() error, error) {
+	if *o.path == "" {
+		return os.Stdout, func() error { return nil }, nil
+	}
+	f, err := os.Create(*o.path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, f.Close, nil
+}
+
+func (o *outputFlags) encode(w io.Writer, v any) error {
+	format := o.format.value
+	if format == "parquet" {
+		if len(o.fields) > 0 {
+			return errors.New("-fields is not supported with -format parquet")
+		}
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice {
+			// A single record, or a pointer to one, becomes a one-row file;
+			// a nil pointer gives a file with no rows.
+			et, n := rv.Type(), 1
+			if rv.Kind() == reflect.Pointer {
+				et = et.Elem()
+				if rv.IsNil() {
+					n = 0
+				} else {
+					rv = rv.Elem()
+				}
+			}
+			s := reflect.MakeSlice(reflect.SliceOf(et), n, n)
+			if n > 0 {
+				s.Index(0).Set(rv)
+			}
+			v = s.Interface()
+		}
+		return lib.WriteParquet(w, v)
+	}
+	if format == "json" && len(o.fields) == 0 {
+		return json.NewEncoder(w).Encode(v)
+	}
+	t, err := newFormatTable(format, v, o.fields)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case "json":
+		if !t.list {
+			if len(t.rows) == 0 {
+				return json.NewEncoder(w).Encode(nil)
+			}
+			return json.NewEncoder(w).Encode(t.object(0))
+		}
+		objs := make([]orderedMap, len(t.rows))
+		for i := range t.rows {
+			objs[i] = t.object(i)
+		}
+		return json.NewEncoder(w).Encode(objs)
+	case "ndjson":
+		enc := json.NewEncoder(w)
+		for i := range t.rows {
+			if err := enc.Encode(t.object(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "csv":
+		cw := csv.NewWriter(w)
+		if err := cw.Write(t.cols); err != nil {
+			return err
+		}
+		for i := range t.rows {
+			if err := cw.Write(t.strings(i)); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	default: // table
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, strings.Join(t.cols, "\t"))
+		for i := range t.rows {
+			cells := t.strings(i)
+			for j, c := range cells {
+				cells[j] = cleanCell(c)
+			}
+			_, _ = fmt.Fprintln(tw, strings.Join(cells, "\t"))
+		}
+		return tw.Flush()
+	}
+}
+
+// stream returns a writer for commands that emit records one at a time.
+// json and ndjson write one object per line, csv writes the header before
+// the first record and table reprints the header, widened, whenever a record
+// has a cell wider than its column.
+func (o *outputFlags) stream() (*outputStream, error) {
+	if o.format.value == "parquet" {
+		return nil, errors.New("-format parquet is not supported for streams")
+	}
+	w, closeOut, err := o.open()
+	if err != nil {
+		return nil, err
+	}
+	return &outputStream{o: o, w: w, close: closeOut}, nil
+}
+
+type outputStream struct {
+	o      *outputFlags
+	w      io.Writer
+	close  func() error
+	cols   []string
+	widths []int
+}
+
+func (s *outputStream) write(v any) error {
+	format := s.o.format.value
+	if (format == "json" || format == "ndjson") && len(s.o.fields) == 0 {
+		return json.NewEncoder(s.w).Encode(v)
+	}
+	fields := s.o.fields
+	if s.cols != nil {
+		fields = s.cols
+	}
+	t, err := newFormatTable(format, v, fields)
+	if err != nil {
+		return err
+	}
+	if len(t.rows) == 0 {
+		return nil
+	}
+	first := s.cols == nil
+	s.cols = t.cols
+	switch format {
+	case "json", "ndjson":
+		return json.NewEncoder(s.w).Encode(t.object(0))
+	case "csv":
+		cw := csv.NewWriter(s.w)
+		if first {
+			_ = cw.Write(t.cols)
+		}
+		_ = cw.Write(t.strings(0))
+		cw.Flush()
+		return cw.Error()
+	default: // table
+		cells := t.strings(0)
+		if first {
+			s.widths = make([]int, len(t.cols))
+			for i := range t.cols {
+				s.widths[i] = len(t.cols[i])
+			}
+		}
+		// The last column is not padded, so only the others can grow.
+		grown := first
+		for i := 0; i+1 < len(cells); i++ {
+			if n := len(cleanCell(cells[i])); n > s.widths[i] {
+				s.widths[i], grown = n, true
+			}
+		}
+		if grown {
+			// Wider cells restart the table so every row stays aligned
+			// with the header above it.
+			if !first {
+				if _, err := fmt.Fprintln(s.w); err != nil {
+					return err
+				}
+			}
+			if _, err := fmt.Fprintln(s.w, padRow(t.cols, s.widths)); err != nil {
+				return err
+			}
+		}
+		_, err := fmt.Fprintln(s.w, padRow(cells, s.widths))
+		return err
+	}
+}
+
+// cleanCell replaces tabs and newlines, which would break table rows.
+func cleanCell(c string) string {
+	return strings.NewReplacer("\t", " ", "\n", " ").Replace(c)
+}
+
+func padRow(cells []string, widths []int) string {
+	var b strings.Builder
+	for i, c := range cells {
+		if i > 0 {
+			b.WriteString("  ")
+		}
+		c = cleanCell(c)
+		b.WriteString(c)
+		if i < len(cells)-1 && len(c) < widths[i] {
+			b.WriteString(strings.Repeat(" ", widths[i]-len(c)))
+		}
+	}
+	return b.String()
+}
+
+// table is v flattened into rows of named cells. Nested objects become
+// "parent.child" columns; arrays stay as JSON.
+type table struct {
+	cols []string
+	rows []map[string]any
+	// list reports whether v was a slice.
+	list bool
+}
+
+func newTable(v any, fields []string) (*table, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	doc, err := decodeOrdered(dec)
+	if err != nil {
+		return nil, err
+	}
+	t := &table{}
+	var items []any
+	switch d := doc.(type) {
+	case []any:
+		t.list, items = true, d
+	case nil:
+		t.list = reflect.ValueOf(v).Kind() == reflect.Slice
+	default:
+		items = []any{d}
+	}
+	seen := map[string]bool{}
+	addCol := func(c string) {
+		if !seen[c] {
+			seen[c] = true
+			t.cols = append(t.cols, c)
+		}
+	}
+	for _, c := range typeColumns(reflect.TypeOf(v), "") {
+		addCol(c)
+	}
+	for _, it := range items {
+		row := map[string]any{}
+		if m, ok := it.(orderedMap); ok {
+			flattenInto(row, "", m, addCol)
+		} else {
+			row["value"] = it
+			addCol("value")
+		}
+		t.rows = append(t.rows, row)
+	}
+	return t, t.pick(fields)
+}
+
+// newFormatTable is newTable for json and ndjson, and csvTable for csv and
+// table when v holds library records.
+func newFormatTable(format string, v any, fields []string) (*table, error) {
+	if format == "csv" || format == "table" {
+		if t, ok, err := csvTable(v, fields); ok || err != nil {
+			return t, err
+		}
+	}
+	return newTable(v, fields)
+}
+
+// csvTable builds the table of a record (or slice of records) with csv tags
+// through lib.WriteCSV, so csv and table output keep the library's CSV
+// contract: columns follow csv tags and fields tagged csv:"-" are left out.
+// ok is false for other values, which go through newTable.
+func csvTable(v any, fields []string) (t *table, ok bool, err error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil, false, nil
+	}
+	list := rv.Kind() == reflect.Slice
+	et := rv.Type()
+	if list {
+		et = et.Elem()
+	}
+	if !hasCSVTags(et) {
+		return nil, false, nil
+	}
+	if !list {
+		s := reflect.MakeSlice(reflect.SliceOf(et), 1, 1)
+		s.Index(0).Set(rv)
+		rv = s
+	}
+	n := rv.Len()
+	if n == 0 {
+		// WriteCSV writes nothing for an empty slice; a zero record still
+		// gives the header.
+		rv = reflect.MakeSlice(rv.Type(), 1, 1)
+	}
+	var buf bytes.Buffer
+	if err := lib.WriteCSV(&buf, rv.Interface()); err != nil {
+		return nil, true, err
+	}
+	recs, err := csv.NewReader(&buf).ReadAll()
+	if err != nil || len(recs) == 0 {
+		return nil, true, err
+	}
+	t = &table{cols: recs[0], list: list}
+	for _, rec := range recs[1 : n+1] {
+		row := make(map[string]any, len(rec))
+		for j, c := range t.cols {
+			row[c] = rec[j]
+		}
+		t.rows = append(t.rows, row)
+	}
+	return t, true, t.pick(fields)
+}
+
+// hasCSVTags reports whether t is a struct with at least one csv tag.
+func hasCSVTags(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if _, ok := t.Field(i).Tag.Lookup("csv"); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// pick restricts the columns to fields, in that order, if any are given.
+func (t *table) pick(fields []string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	for _, f := range fields {
+		if !slices.Contains(t.cols, f) {
+			return fmt.Errorf("unknown field %q (available: %s)", f, strings.Join(t.cols, ","))
+		}
+	}
+	t.cols = fields
+	return nil
+}
+
+func flattenInto(row map[string]any, prefix string, m orderedMap, addCol func(string)) {
+	for _, k := range m.keys {
+		if sub, ok := m.vals[k].(orderedMap); ok && len(sub.keys) > 0 {
+			flattenInto(row, prefix+k+".", sub, addCol)
+			continue
+		}
+		row[prefix+k] = m.vals[k]
+		addCol(prefix + k)
+	}
+}
+
+var jsonMarshaler = reflect.TypeFor[json.Marshaler]()
+
+// typeColumns lists the flattened JSON names of a struct (or slice of
+// structs) type so columns keep field order even when values are omitted.
+func typeColumns(t reflect.Type, prefix string) []string {
+	if t == nil {
+		return nil
+	}
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		if prefix != "" && t.Kind() != reflect.Pointer {
+			return []string{strings.TrimSuffix(prefix, ".")}
+		}
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t.Implements(jsonMarshaler) || reflect.PointerTo(t).Implements(jsonMarshaler) {
+		if prefix != "" {
+			return []string{strings.TrimSuffix(prefix, ".")}
+		}
+		return nil
+	}
+	var cols []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		cols = append(cols, typeColumns(f.Type, prefix+name+".")...)
+	}
+	return cols
+}
+
+func (t *table) object(i int) orderedMap {
+	m := orderedMap{vals: map[string]any{}}
+	for _, c := range t.cols {
+		m.keys = append(m.keys, c)
+		m.vals[c] = t.rows[i][c]
+	}
+	return m
+}
+
+func (t *table) strings(i int) []string {
+	out := make([]string, len(t.cols))
+	for j, c := range t.cols {
+		out[j] = cellString(t.rows[i][c])
+	}
+	return out
+}
+
+func cellString(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case json.Number:
+		return x.String()
+	case bool:
+		if x {
+			return "true"
+		}
+		return "false"
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// orderedMap is a JSON object that keeps its key order.
+type orderedMap struct {
+	keys []string
+	vals map[string]any
+}
+
+func (m orderedMap) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, k := range m.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		kb, _ := json.Marshal(k)
+		b.Write(kb)
+		b.WriteByte(':')
+		vb, err := json.Marshal(m.vals[k])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(vb)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+func decodeOrdered(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch d := tok.(type) {
+	case json.Delim:
+		if d == '[' {
+			arr := []any{}
+			for dec.More() {
+				v, err := decodeOrdered(dec)
+				if err != nil {
+					return nil, err
+				}
+				arr = append(arr, v)
+			}
+			_, err := dec.Token()
+			return arr, err
+		}
+		m := orderedMap{vals: map[string]any{}}
+		for dec.More() {
+			kt, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			k, _ := kt.(string)
+			v, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			if _, dup := m.vals[k]; !dup {
+				m.keys = append(m.keys, k)
+			}
+			m.vals[k] = v
+		}
+		_, err := dec.Token()
+		return m, err
+	}
+	return tok, nil
+}
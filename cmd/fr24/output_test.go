@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	lib "github.com/igolaizola/fr24/pkg/flightradar"
+)
+
+func newTestOutput(format string, fields ...string) *outputFlags {
+	return &outputFlags{format: choiceFlag{value: format}, fields: fields, path: new(string)}
+}
+
+func TestEncodeCSVFollowsCSVTags(t *testing.T) {
+	cs := "ABC123"
+	recs := []lib.PlaybackFlight{{
+		Callsign: &cs,
+		Track:    []lib.PlaybackTrack{{}},
+	}}
+	var want bytes.Buffer
+	if err := lib.WriteCSV(&want, recs); err != nil {
+		t.Fatal(err)
+	}
+	var got bytes.Buffer
+	if err := newTestOutput("csv").encode(&got, recs); err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != want.String() {
+		t.Fatalf("csv output differs from WriteCSV:\ngot  %q\nwant %q", got.String(), want.String())
+	}
+	if strings.Contains(got.String(), "track") {
+		t.Errorf("csv:\"-\" field leaked: %s", got.String())
+	}
+
+	// playback outputs a single *PlaybackFlight.
+	got.Reset()
+	if err := newTestOutput("csv").encode(&got, &recs[0]); err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != want.String() {
+		t.Fatalf("pointer record differs from WriteCSV:\ngot  %q\nwant %q", got.String(), want.String())
+	}
+}
+
+func TestEncodeCSVNested(t *testing.T) {
+	recs := []lib.NearbyFlightRecord{{DistanceM: 12}}
+	var got bytes.Buffer
+	if err := newTestOutput("csv").encode(&got, recs); err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != "distance\n12\n" {
+		t.Errorf("got %q", got.String())
+	}
+	if err := newTestOutput("csv", "live.callsign").encode(&got, recs); err == nil {
+		t.Error("expected unknown field error for a csv:\"-\" column")
+	}
+}
+
+func TestEncodeCSVFields(t *testing.T) {
+	recs := []lib.LiveFlightsStatusRecord{{FlightID: 1, Squawk: 7700}, {FlightID: 2}}
+	var got bytes.Buffer
+	if err := newTestOutput("csv", "squawk", "flight_id").encode(&got, recs); err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != "squawk,flight_id\n7700,1\n0,2\n" {
+		t.Errorf("got %q", got.String())
+	}
+	got.Reset()
+	if err := newTestOutput("csv").encode(&got, []lib.LiveFlightsStatusRecord{}); err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != "flight_id,latitude,longitude,status,squawk\n" {
+		t.Errorf("empty slice: got %q", got.String())
+	}
+}
+
+func TestEncodeCSVWithoutCSVTags(t *testing.T) {
+	checks := []doctorCheck{{Check: "echo", Status: "ok"}}
+	var got bytes.Buffer
+	if err := newTestOutput("csv").encode(&got, checks); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(got.String(), "check,status,latency_ms,detail\n") {
+		t.Errorf("got %q", got.String())
+	}
+}
+
+func TestEncodeParquetPointerRecord(t *testing.T) {
+	cs := "ABC123"
+	var buf bytes.Buffer
+	if err := newTestOutput("parquet").encode(&buf, &lib.PlaybackFlight{Callsign: &cs}); err != nil {
+		t.Fatal(err)
+	}
+	var got []lib.PlaybackFlight
+	if err := lib.ReadParquet(bytes.NewReader(buf.Bytes()), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Callsign == nil || *got[0].Callsign != cs {
+		t.Fatalf("got %+v", got)
+	}
+
+	buf.Reset()
+	if err := newTestOutput("parquet").encode(&buf, (*lib.PlaybackFlight)(nil)); err != nil {
+		t.Fatal(err)
+	}
+	got = nil
+	if err := lib.ReadParquet(bytes.NewReader(buf.Bytes()), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("nil record: got %d rows", len(got))
+	}
+}
+
+func TestStreamTableAligns(t *testing.T) {
+	var got bytes.Buffer
+	s := &outputStream{o: newTestOutput("table"), w: &got, close: func() error { return nil }}
+	for _, r := range []lib.LiveFlightsStatusRecord{
+		{FlightID: 1, Squawk: 7700},
+		{FlightID: 1234567890, Latitude: 43.25},
+		{FlightID: 2},
+	} {
+		if err := s.write(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := "" +
+		"flight_id  latitude  longitude  status  squawk\n" +
+		"1          0         0          0       7700\n" +
+		"\n" +
+		"flight_id   latitude  longitude  status  squawk\n" +
+		"1234567890  43.25     0          0       0\n" +
+		"2           0         0          0       0\n"
+	if got.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", got.String(), want)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"iter"
+
+	lib "github.com/igolaizola/fr24/pkg/flightradar"
+)
+
+// pageFlags selects the page size and whether to walk every page of a
+// paginated list.
+type pageFlags struct {
+	limit    *int
+	all      *bool
+	maxPages *int
+	beforeTS *string
+}
+
+func addPageFlags(fs *flag.FlagSet) *pageFlags {
+	return &pageFlags{
+		limit:    fs.Int("limit", 10, "results per page (max 100)"),
+		all:      fs.Bool("all", false, "follow pagination and output every page"),
+		maxPages: fs.Int("max-pages", 0, "with -all, stop after N requests (0 = no limit)"),
+		beforeTS: fs.String("before", "", "list as of this time (unix seconds or RFC 3339); with -all, walks back from here"),
+	}
+}
+
+func (p *pageFlags) before() (*int64, error) {
+	if *p.beforeTS == "" {
+		return nil, nil
+	}
+	ts, ok := lib.ToUnixSeconds(*p.beforeTS)
+	if !ok {
+		return nil, fmt.Errorf("invalid -before %q", *p.beforeTS)
+	}
+	return &ts, nil
+}
+
+// writeAll collects a paginated sequence and writes it. Records fetched
+// before an error are still written.
+func writeAll[T any](out *outputFlags, seq iter.Seq2[T, error]) error {
+	var recs []T
+	var ferr error
+	for r, err := range seq {
+		if err != nil {
+			ferr = err
+			break
+		}
+		recs = append(recs, r)
+	}
+	if recs == nil {
+		recs = []T{}
+	}
+	if err := out.write(recs); err != nil {
+		return err
+	}
+	return ferr
+}
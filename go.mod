@@ -3,6 +3,7 @@ module github.com/igolaizola/fr24
 go 1.25.0
 
 require (
+	github.com/klauspost/compress v1.18.0
 	github.com/peterbourgon/ff/v3 v3.4.0
 	google.golang.org/protobuf v1.34.1
 )
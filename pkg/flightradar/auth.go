@@ -2,6 +2,7 @@ package flightradar
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -26,7 +27,10 @@ type Authentication struct {
 func (c *Client) LoginFromEnvOrConfig() error {
     creds := readCredentials()
     if creds.username != "" && creds.password != "" {
-        auth, err := loginWithUsernamePassword(c.http, creds.username, creds.password)
+        if err := c.wait(context.Background(), familyLogin); err != nil {
+            return err
+        }
+        auth, err := loginWithUsernamePassword(c.http, c.endpoints.Web, creds.username, creds.password)
         if err != nil {
             return err
         }
@@ -74,47 +78,58 @@ func readCredentials() credentials {
 		token:           os.Getenv("fr24_token"),
 	}
 	// optional INI file override
-	if dir, err := os.UserConfigDir(); err == nil {
-		fp := filepath.Join(dir, "fr24", "fr24.conf")
-        if f, err := os.Open(fp); err == nil {
-            defer func() { _ = f.Close() }()
-			// very small INI reader for [global] key=value
-			s := bufio.NewScanner(f)
-			inGlobal := false
-			for s.Scan() {
-				ln := strings.TrimSpace(s.Text())
-				if ln == "" || strings.HasPrefix(ln, ";") || strings.HasPrefix(ln, "#") {
-					continue
-				}
-				if strings.HasPrefix(ln, "[") {
-					inGlobal = strings.EqualFold(ln, "[global]")
-					continue
-				}
-				if !inGlobal {
-					continue
-				}
-				if i := strings.Index(ln, "="); i > 0 {
-					k := strings.TrimSpace(ln[:i])
-					v := strings.TrimSpace(ln[i+1:])
-					switch k {
-					case "username":
-						c.username = v
-					case "password":
-						c.password = v
-					case "subscription_key":
-						c.subscriptionKey = v
-					case "token":
-						c.token = v
-					}
-				}
-			}
+	for k, v := range readConfig() {
+		switch k {
+		case "username":
+			c.username = v
+		case "password":
+			c.password = v
+		case "subscription_key":
+			c.subscriptionKey = v
+		case "token":
+			c.token = v
 		}
 	}
 	return c
 }
 
-func loginWithUsernamePassword(httpc *http.Client, username, password string) (Authentication, error) {
-	req, _ := http.NewRequest("POST", "https://www.flightradar24.com/user/login", strings.NewReader("email="+urlEncode(username)+"&password="+urlEncode(password)))
+// readConfig returns the key=value pairs of the [global] section of
+// $XDG_CONFIG_HOME/fr24/fr24.conf, or nil if there is no config file.
+func readConfig() map[string]string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return nil
+	}
+	f, err := os.Open(filepath.Join(dir, "fr24", "fr24.conf"))
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = f.Close() }()
+	// very small INI reader for [global] key=value
+	cfg := map[string]string{}
+	s := bufio.NewScanner(f)
+	inGlobal := false
+	for s.Scan() {
+		ln := strings.TrimSpace(s.Text())
+		if ln == "" || strings.HasPrefix(ln, ";") || strings.HasPrefix(ln, "#") {
+			continue
+		}
+		if strings.HasPrefix(ln, "[") {
+			inGlobal = strings.EqualFold(ln, "[global]")
+			continue
+		}
+		if !inGlobal {
+			continue
+		}
+		if i := strings.Index(ln, "="); i > 0 {
+			cfg[strings.TrimSpace(ln[:i])] = strings.TrimSpace(ln[i+1:])
+		}
+	}
+	return cfg
+}
+
+func loginWithUsernamePassword(httpc *http.Client, webURL, username, password string) (Authentication, error) {
+	req, _ := http.NewRequest("POST", webURL+"/user/login", strings.NewReader("email="+urlEncode(username)+"&password="+urlEncode(password)))
 	for k, vs := range DEFAULT_JSON_HEADERS_NOAUTH() {
 		for _, v := range vs {
 			req.Header.Add(k, v)
@@ -1,12 +1,55 @@
 package flightradar
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
+
+	pb "github.com/igolaizola/fr24/pkg/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// Cache kinds; each is a subdirectory of the cache base.
+const (
+	CacheLiveFeed       = "live_feed"
+	CachePlayback       = "playback"
+	CacheFlightDetails  = "flight_details"
+	CachePlaybackFlight = "playback_flight"
+	CacheFlightList     = "flight_list"
+)
+
+// FR24Cache stores records and raw historic responses on disk. Records are
+// CSV (or Parquet, see WithFormat) files under <base>/<kind>/; responses used
+// by Client.WithCache live under <base>/responses/<kind>/. Historic data does
+// not change, so entries never expire.
+type FR24Cache struct {
+	base   string
+	format FileFormat
+}
+
+// FileFormat is the on-disk format of cached records.
+type FileFormat string
+
+const (
+	FormatCSV     FileFormat = "csv"
+	FormatParquet FileFormat = "parquet"
 )
 
-type FR24Cache struct{ base string }
+// ParseFileFormat parses "csv" or "parquet".
+func ParseFileFormat(s string) (FileFormat, error) {
+	switch f := FileFormat(strings.ToLower(strings.TrimSpace(s))); f {
+	case FormatCSV, FormatParquet:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown file format %q: want csv or parquet", s)
+}
 
 func DefaultCache() (*FR24Cache, error) {
 	dir, err := os.UserCacheDir()
@@ -20,17 +63,295 @@ func DefaultCache() (*FR24Cache, error) {
 	return &FR24Cache{base: base}, nil
 }
 
+// NewCache returns a cache rooted at base (e.g. an archive output directory).
+func NewCache(base string) *FR24Cache { return &FR24Cache{base: base} }
+
+// WithFormat sets the record file format; the default is FormatCSV.
+func (c *FR24Cache) WithFormat(f FileFormat) *FR24Cache {
+	c.format = f
+	return c
+}
+
+func (c *FR24Cache) ext() string {
+	if c.format == "" {
+		return string(FormatCSV)
+	}
+	return string(c.format)
+}
+
 func (c *FR24Cache) LiveFeedPath(ts int64) string {
-	return filepath.Join(c.base, "live_feed", fmt.Sprintf("%d.csv", ts))
+	return filepath.Join(c.base, CacheLiveFeed, fmt.Sprintf("%d.%s", ts, c.ext()))
 }
 func (c *FR24Cache) PlaybackPath(flightID string) string {
-	return filepath.Join(c.base, "playback", fmt.Sprintf("%s.csv", flightID))
+	return filepath.Join(c.base, CachePlayback, fmt.Sprintf("%s.%s", flightID, c.ext()))
 }
 func (c *FR24Cache) FlightDetailsPath(fid uint32, ts int64) string {
-	return filepath.Join(c.base, "flight_details", fmt.Sprintf("%d_%d.csv", fid, ts))
+	return filepath.Join(c.base, CacheFlightDetails, fmt.Sprintf("%d_%d.%s", fid, ts, c.ext()))
 }
 func (c *FR24Cache) PlaybackFlightPath(fid uint32, ts uint64) string {
-	return filepath.Join(c.base, "playback_flight", fmt.Sprintf("%d_%d.csv", fid, ts))
+	return filepath.Join(c.base, CachePlaybackFlight, fmt.Sprintf("%d_%d.%s", fid, ts, c.ext()))
+}
+
+// ResponsePath returns where the raw response body for key of kind is kept.
+func (c *FR24Cache) ResponsePath(kind, key string) string {
+	return filepath.Join(c.base, "responses", kind, key)
 }
 
 func (c *FR24Cache) Base() string { return c.base }
+
+// Load* functions return an error matching fs.ErrNotExist when the entry is
+// not stored.
+
+// LiveFeedExists reports whether a live feed snapshot for ts is stored.
+func (c *FR24Cache) LiveFeedExists(ts int64) bool { return fileExists(c.LiveFeedPath(ts)) }
+
+// SaveLiveFeed writes a live feed snapshot to LiveFeedPath(ts).
+func (c *FR24Cache) SaveLiveFeed(ts int64, recs []LiveFeedFlightRecord) error {
+	return writeRecordsFile(c.LiveFeedPath(ts), recs)
+}
+
+// LoadLiveFeed reads the live feed snapshot stored for ts.
+func (c *FR24Cache) LoadLiveFeed(ts int64) ([]LiveFeedFlightRecord, error) {
+	return readRecordsFile[LiveFeedFlightRecord](c.LiveFeedPath(ts))
+}
+
+// PlaybackExists reports whether the playback track of flightID is stored.
+func (c *FR24Cache) PlaybackExists(flightID string) bool {
+	return fileExists(c.PlaybackPath(flightID))
+}
+
+// SavePlayback writes a playback track to PlaybackPath(flightID).
+func (c *FR24Cache) SavePlayback(flightID string, recs []PlaybackTrack) error {
+	return writeRecordsFile(c.PlaybackPath(flightID), recs)
+}
+
+// LoadPlayback reads the playback track stored for flightID.
+func (c *FR24Cache) LoadPlayback(flightID string) ([]PlaybackTrack, error) {
+	return readRecordsFile[PlaybackTrack](c.PlaybackPath(flightID))
+}
+
+// FlightDetailsExists reports whether flight details for fid at ts are stored.
+func (c *FR24Cache) FlightDetailsExists(fid uint32, ts int64) bool {
+	return fileExists(c.FlightDetailsPath(fid, ts))
+}
+
+// SaveFlightDetails writes flight details to FlightDetailsPath(fid, ts).
+func (c *FR24Cache) SaveFlightDetails(fid uint32, ts int64, rec FlightDetailsRecord) error {
+	return writeRecordsFile(c.FlightDetailsPath(fid, ts), []FlightDetailsRecord{rec})
+}
+
+// LoadFlightDetails reads the flight details stored for fid at ts.
+func (c *FR24Cache) LoadFlightDetails(fid uint32, ts int64) (FlightDetailsRecord, error) {
+	return readRecord[FlightDetailsRecord](c.FlightDetailsPath(fid, ts))
+}
+
+// PlaybackFlightExists reports whether a playback flight for fid at ts is stored.
+func (c *FR24Cache) PlaybackFlightExists(fid uint32, ts uint64) bool {
+	return fileExists(c.PlaybackFlightPath(fid, ts))
+}
+
+// SavePlaybackFlight writes a playback flight to PlaybackFlightPath(fid, ts).
+func (c *FR24Cache) SavePlaybackFlight(fid uint32, ts uint64, rec PlaybackFlightRecord) error {
+	return writeRecordsFile(c.PlaybackFlightPath(fid, ts), []PlaybackFlightRecord{rec})
+}
+
+// LoadPlaybackFlight reads the playback flight stored for fid at ts.
+func (c *FR24Cache) LoadPlaybackFlight(fid uint32, ts uint64) (PlaybackFlightRecord, error) {
+	return readRecord[PlaybackFlightRecord](c.PlaybackFlightPath(fid, ts))
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func readRecordsFile[T any](path string) ([]T, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	read := ReadCSV
+	if filepath.Ext(path) == "."+string(FormatParquet) {
+		read = ReadParquet
+	}
+	var out []T
+	if err := read(f, &out); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return out, nil
+}
+
+func readRecord[T any](path string) (T, error) {
+	recs, err := readRecordsFile[T](path)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	if len(recs) == 0 {
+		var zero T
+		return zero, fmt.Errorf("%s: empty cache entry", path)
+	}
+	return recs[0], nil
+}
+
+// writeRecordsFile writes records (CSV or Parquet, by extension) through a
+// temporary file and renames it into place, so an interrupted run never
+// leaves a partial file behind.
+func writeRecordsFile(path string, slice any) error {
+	write := WriteCSV
+	if filepath.Ext(path) == "."+string(FormatParquet) {
+		write = WriteParquet
+	}
+	return writeFileAtomic(path, func(w io.Writer) error { return write(w, slice) })
+}
+
+func writeFileAtomic(path string, write func(io.Writer) error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if err := write(tmp); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// ---- read-through responses ----
+
+// WithCache serves historic calls (JSON playback and flight list with an
+// explicit timestamp, and the PlaybackFlight RPC) from cache, storing
+// successful responses on a miss. Timestamps newer than CacheMinAge always
+// go to the network. Pass nil to disable. Cached responses
+// carry the "X-Fr24-Cache: hit" header.
+func (c *Client) WithCache(cache *FR24Cache) *Client {
+	c.cache = cache
+	return c
+}
+
+// Cache returns the cache set with WithCache, if any.
+func (c *Client) Cache() *FR24Cache { return c.cache }
+
+// cacheHeader marks responses served from disk.
+const cacheHeader = "X-Fr24-Cache"
+
+// CacheMinAge is how old a requested timestamp must be before its response
+// is cached; younger flights may still be airborne and change.
+var CacheMinAge = 24 * time.Hour
+
+func historic(ts int64) bool { return time.Since(time.Unix(ts, 0)) >= CacheMinAge }
+
+// doCached serves req from the response cache entry kind/key, or performs it
+// and stores the body if store accepts it. store may rewrite the body that
+// is saved (the caller still sees the original).
+func (c *Client) doCached(ctx context.Context, req *http.Request, kind, key, contentType string, store func(body []byte, h http.Header) ([]byte, bool)) (*http.Response, error) {
+	if c.cache == nil || key == "" {
+		return c.do(ctx, req)
+	}
+	path := c.cache.ResponsePath(kind, key)
+	if body, err := os.ReadFile(path); err == nil {
+		return &http.Response{
+			Status:        "200 OK",
+			StatusCode:    http.StatusOK,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": {contentType}, cacheHeader: {"hit"}},
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+	resp, err := c.do(ctx, req)
+	if err != nil || resp.StatusCode != http.StatusOK || headerStatus(resp.Header) != nil {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if data, ok := store(body, resp.Header); ok {
+		// a failed write only costs a refetch next time
+		_ = writeFileAtomic(path, func(w io.Writer) error {
+			_, err := w.Write(data)
+			return err
+		})
+	}
+	return resp, nil
+}
+
+// storeJSONResult accepts JSON API bodies that carry a result and no errors.
+func storeJSONResult(body []byte, _ http.Header) ([]byte, bool) {
+	var root struct {
+		Result json.RawMessage `json:"result"`
+		Errors json.RawMessage `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &root); err != nil {
+		return nil, false
+	}
+	return body, len(root.Result) > 0 && string(root.Result) != "null" && len(root.Errors) == 0
+}
+
+// storeGRPC accepts gRPC-web bodies with a message and an OK status. Frames
+// are stored decompressed so a hit decodes without the grpc-encoding header.
+func storeGRPC(body []byte, h http.Header) ([]byte, bool) {
+	var out bytes.Buffer
+	var messages int
+	fr := NewFrameReader(bytes.NewReader(body))
+	for {
+		f, err := fr.Next()
+		if err == io.EOF {
+			return out.Bytes(), messages > 0
+		}
+		if err != nil {
+			return nil, false
+		}
+		if f.IsTrailer() {
+			if trailerStatus(f) != nil {
+				return nil, false
+			}
+			out.Write(f.Bytes())
+			continue
+		}
+		if f.IsCompressed() {
+			data, err := decompress(h.Get("grpc-encoding"), f.Payload)
+			if err != nil {
+				return nil, false
+			}
+			f = Frame{Payload: data}
+		}
+		messages++
+		out.Write(f.Bytes())
+	}
+}
+
+// grpcCacheKey returns the response cache entry for historic RPCs.
+func grpcCacheKey(method string, msg proto.Message) (kind, key string) {
+	switch m := msg.(type) {
+	case *pb.PlaybackFlightRequest:
+		if method == "PlaybackFlight" && m.GetTimestamp() != 0 && historic(int64(m.GetTimestamp())) {
+			return CachePlaybackFlight, fmt.Sprintf("%d_%d_%d.grpc", m.GetFlightId(), m.GetTimestamp(), m.GetRestrictionMode())
+		}
+	}
+	return "", ""
+}
+
+// cacheKeyPart makes a query value safe to use in a file name.
+func cacheKeyPart(s string) string {
+	return strings.Map(func(r rune) rune {
+		if isAlnum(string(r)) || r == '-' {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(s))
+}
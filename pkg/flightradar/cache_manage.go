@@ -0,0 +1,238 @@
+package flightradar
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CacheEntry is one file in the cache tree.
+type CacheEntry struct {
+	// Kind is the directory relative to the base, e.g. "live_feed" or
+	// "responses/playback_flight".
+	Kind string `json:"kind"`
+	// Key is the file name without extension.
+	Key     string    `json:"key"`
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	// Timestamp is the unix seconds the entry describes, taken from the last
+	// key part that looks like one (0 if none, e.g. playback by flight id).
+	Timestamp int64 `json:"timestamp,omitempty"`
+}
+
+// CacheKindStats summarises the entries of one kind.
+type CacheKindStats struct {
+	Kind   string    `json:"kind"`
+	Count  int       `json:"count"`
+	Bytes  int64     `json:"bytes"`
+	Oldest time.Time `json:"oldest"`
+	Newest time.Time `json:"newest"`
+}
+
+// PruneOptions selects entries to delete. Both limits may be combined.
+type PruneOptions struct {
+	// OlderThan removes entries last written before now-OlderThan.
+	OlderThan time.Duration
+	// MaxSize removes the oldest entries until the cache fits in MaxSize bytes.
+	MaxSize int64
+	// DryRun reports what would be removed without deleting anything.
+	DryRun bool
+}
+
+// Entries lists the cache entries of kind (and its sub-kinds), or all
+// entries if kind is empty, sorted by kind and key.
+func (c *FR24Cache) Entries(kind string) ([]CacheEntry, error) {
+	var out []CacheEntry
+	err := filepath.WalkDir(c.base, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == c.base && os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+		rel, err := filepath.Rel(c.base, filepath.Dir(path))
+		if err != nil || rel == "." {
+			return err
+		}
+		k := filepath.ToSlash(rel)
+		if kind != "" && k != kind && !strings.HasPrefix(k, kind+"/") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		key := strings.TrimSuffix(d.Name(), filepath.Ext(d.Name()))
+		out = append(out, CacheEntry{
+			Kind:      k,
+			Key:       key,
+			Path:      path,
+			Size:      info.Size(),
+			ModTime:   info.ModTime(),
+			Timestamp: keyTimestamp(key),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Kind != out[j].Kind {
+			return out[i].Kind < out[j].Kind
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out, nil
+}
+
+// Stats returns per-kind counts, sizes and write times.
+func (c *FR24Cache) Stats() ([]CacheKindStats, error) {
+	entries, err := c.Entries("")
+	if err != nil {
+		return nil, err
+	}
+	var out []CacheKindStats
+	for _, e := range entries {
+		if len(out) == 0 || out[len(out)-1].Kind != e.Kind {
+			out = append(out, CacheKindStats{Kind: e.Kind, Oldest: e.ModTime, Newest: e.ModTime})
+		}
+		s := &out[len(out)-1]
+		s.Count++
+		s.Bytes += e.Size
+		if e.ModTime.Before(s.Oldest) {
+			s.Oldest = e.ModTime
+		}
+		if e.ModTime.After(s.Newest) {
+			s.Newest = e.ModTime
+		}
+	}
+	return out, nil
+}
+
+// Prune deletes entries by age and total size and returns the removed ones.
+func (c *FR24Cache) Prune(opts PruneOptions) ([]CacheEntry, error) {
+	entries, err := c.Entries("")
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].ModTime.Before(entries[j].ModTime) })
+	var total int64
+	for _, e := range entries {
+		total += e.Size
+	}
+	cutoff := time.Now().Add(-opts.OlderThan)
+	var removed []CacheEntry
+	for _, e := range entries {
+		old := opts.OlderThan > 0 && e.ModTime.Before(cutoff)
+		over := opts.MaxSize > 0 && total > opts.MaxSize
+		if !old && !over {
+			continue
+		}
+		if !opts.DryRun {
+			if err := os.Remove(e.Path); err != nil && !os.IsNotExist(err) {
+				return removed, err
+			}
+		}
+		total -= e.Size
+		removed = append(removed, e)
+	}
+	return removed, nil
+}
+
+// Remove deletes the entries of kind whose key matches and returns them. It
+// returns an error matching fs.ErrNotExist if nothing matched.
+func (c *FR24Cache) Remove(kind, key string) ([]CacheEntry, error) {
+	dir := filepath.Join(c.base, filepath.FromSlash(kind))
+	if rel, err := filepath.Rel(c.base, dir); err != nil || rel == "." || strings.HasPrefix(rel, "..") || strings.ContainsAny(key, `/\`) {
+		return nil, fmt.Errorf("invalid cache entry %s/%s", kind, key)
+	}
+	entries, err := c.Entries(filepath.ToSlash(kind))
+	if err != nil {
+		return nil, err
+	}
+	var removed []CacheEntry
+	for _, e := range entries {
+		if e.Kind != kind || e.Key != key {
+			continue
+		}
+		if err := os.Remove(e.Path); err != nil {
+			return removed, err
+		}
+		removed = append(removed, e)
+	}
+	if len(removed) == 0 {
+		return nil, fmt.Errorf("cache entry %s/%s: %w", kind, key, fs.ErrNotExist)
+	}
+	return removed, nil
+}
+
+// Export writes the entries of kind whose Timestamp lies within [from, to]
+// to w as a tar stream, with names relative to the base. Zero bounds are
+// open; entries without a timestamp are only exported when both are zero.
+// It returns the number of files written.
+func (c *FR24Cache) Export(w io.Writer, kind string, from, to time.Time) (int, error) {
+	entries, err := c.Entries(kind)
+	if err != nil {
+		return 0, err
+	}
+	tw := tar.NewWriter(w)
+	var n int
+	for _, e := range entries {
+		if !from.IsZero() || !to.IsZero() {
+			if e.Timestamp == 0 || (!from.IsZero() && e.Timestamp < from.Unix()) || (!to.IsZero() && e.Timestamp > to.Unix()) {
+				continue
+			}
+		}
+		if err := addTarFile(tw, c.base, e); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, tw.Close()
+}
+
+func addTarFile(tw *tar.Writer, base string, e CacheEntry) error {
+	f, err := os.Open(e.Path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	name, err := filepath.Rel(base, e.Path)
+	if err != nil {
+		return err
+	}
+	hdr := &tar.Header{
+		Name:    filepath.ToSlash(name),
+		Mode:    0o644,
+		Size:    e.Size,
+		ModTime: e.ModTime,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = io.CopyN(tw, f, e.Size)
+	return err
+}
+
+// keyTimestamp returns the last underscore-separated part of key that looks
+// like unix seconds (after 2001), or 0.
+func keyTimestamp(key string) int64 {
+	parts := strings.Split(key, "_")
+	for i := len(parts) - 1; i >= 0; i-- {
+		if n, err := strconv.ParseInt(parts[i], 10, 64); err == nil && n >= 1e9 && n < 1e11 {
+			return n
+		}
+	}
+	return 0
+}
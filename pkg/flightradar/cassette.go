@@ -0,0 +1,399 @@
+package flightradar
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Interaction is one recorded request/response pair of a cassette.
+type Interaction struct {
+	Method        string      `json:"method"`
+	URL           string      `json:"url"`
+	RequestHeader http.Header `json:"request_header,omitempty"`
+	RequestBody   []byte      `json:"request_body,omitempty"`
+	Status        int         `json:"status"`
+	Header        http.Header `json:"header,omitempty"`
+	// Chunks is the response body. gRPC-web bodies are split into frames;
+	// each chunk carries the time since the response started at which its
+	// last byte was read.
+	Chunks []Chunk `json:"chunks"`
+}
+
+// Chunk is a piece of a recorded response body.
+type Chunk struct {
+	ElapsedMS int64  `json:"elapsed_ms"`
+	Data      []byte `json:"data"`
+}
+
+// redacted replaces secrets in recorded requests and responses.
+const redacted = "REDACTED"
+
+// Query parameters, headers, form fields and JSON keys that are redacted.
+var (
+	redactQuery  = []string{"token", "device"}
+	redactHeader = []string{"Authorization", "Fr24-Device-Id", "Cookie", "Set-Cookie"}
+	redactForm   = []string{"email", "password"}
+	redactJSON   = regexp.MustCompile(`("(?:token|accessToken|subscriptionKey)"\s*:\s*)"[^"]*"`)
+)
+
+// RecordingTransport is an http.RoundTripper that saves every request and
+// response to a cassette directory, one JSON file per interaction, with
+// tokens, device ids, cookies and credentials redacted. Plug it in with
+// Client.WithHTTP. A response is saved once its body is read to the end or
+// closed.
+type RecordingTransport struct {
+	Dir string
+	// Base performs the requests (default http.DefaultTransport).
+	Base http.RoundTripper
+
+	mu sync.Mutex
+	n  int
+}
+
+// NewRecordingTransport records into dir, creating it if needed.
+func NewRecordingTransport(dir string) (*RecordingTransport, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &RecordingTransport{Dir: dir}, nil
+}
+
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		reqBody = b
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(b))
+	}
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	t.mu.Lock()
+	t.n++
+	n := t.n
+	t.mu.Unlock()
+	it := &Interaction{
+		Method:        req.Method,
+		URL:           redactURL(req.URL),
+		RequestHeader: redactHeaders(req.Header),
+		RequestBody:   redactBody(req.Header.Get("Content-Type"), reqBody),
+		Status:        resp.StatusCode,
+		Header:        redactHeaders(resp.Header),
+	}
+	name := fmt.Sprintf("%04d_%s.json", n, cassetteName(req.URL))
+	resp.Body = &recordingBody{rc: resp.Body, start: time.Now(), it: it, path: filepath.Join(t.Dir, name)}
+	return resp, nil
+}
+
+// recordingBody captures a response body with read timings and writes the
+// interaction when the body ends.
+type recordingBody struct {
+	rc    io.ReadCloser
+	start time.Time
+	it    *Interaction
+	path  string
+	buf   bytes.Buffer
+	// ends holds the buffer length and elapsed time after each read.
+	ends []readMark
+	once sync.Once
+}
+
+type readMark struct {
+	n  int
+	ms int64
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.rc.Read(p)
+	if n > 0 {
+		b.buf.Write(p[:n])
+		b.ends = append(b.ends, readMark{n: b.buf.Len(), ms: time.Since(b.start).Milliseconds()})
+	}
+	if err == io.EOF {
+		b.save()
+	}
+	return n, err
+}
+
+func (b *recordingBody) Close() error {
+	b.save()
+	return b.rc.Close()
+}
+
+func (b *recordingBody) save() {
+	b.once.Do(func() {
+		b.it.Chunks = b.chunks()
+		data, err := json.MarshalIndent(b.it, "", "  ")
+		if err == nil {
+			_ = writeFileAtomic(b.path, func(w io.Writer) error {
+				_, err := w.Write(data)
+				return err
+			})
+		}
+	})
+}
+
+// chunks splits the body into gRPC-web frames when it parses as such, and
+// otherwise keeps one chunk per read.
+func (b *recordingBody) chunks() []Chunk {
+	body := b.buf.Bytes()
+	at := func(end int) int64 {
+		i := sort.Search(len(b.ends), func(i int) bool { return b.ends[i].n >= end })
+		if i == len(b.ends) {
+			return time.Since(b.start).Milliseconds()
+		}
+		return b.ends[i].ms
+	}
+	ct := b.it.Header.Get("Content-Type")
+	if strings.HasPrefix(ct, "application/grpc-web") {
+		var out []Chunk
+		for off := 0; off+5 <= len(body); {
+			end := off + 5 + int(binary.BigEndian.Uint32(body[off+1:off+5]))
+			if end > len(body) {
+				break
+			}
+			out = append(out, Chunk{ElapsedMS: at(end), Data: body[off:end]})
+			off = end
+		}
+		if chunkLen(out) == len(body) {
+			return out
+		}
+	}
+	if strings.Contains(ct, "json") {
+		// Secrets may straddle reads, so redact the whole body as one chunk.
+		return []Chunk{{ElapsedMS: at(len(body)), Data: redactJSON.ReplaceAll(body, []byte(`$1"`+redacted+`"`))}}
+	}
+	out := []Chunk{}
+	prev := 0
+	for _, m := range b.ends {
+		out = append(out, Chunk{ElapsedMS: m.ms, Data: body[prev:m.n]})
+		prev = m.n
+	}
+	return out
+}
+
+func chunkLen(cs []Chunk) int {
+	n := 0
+	for _, c := range cs {
+		n += len(c.Data)
+	}
+	return n
+}
+
+// ReplayTransport is an http.RoundTripper that answers requests from a
+// cassette directory written by RecordingTransport, without touching the
+// network. gRPC calls match on the RPC and the request proto bytes, other
+// calls on method, path and query (ignoring redacted parameters); if nothing
+// matches exactly, the next unused interaction for the same method and path
+// is used, so volatile queries such as timestamp=now still replay. Repeated
+// identical requests replay their recordings in order.
+type ReplayTransport struct {
+	// Realtime delays each chunk by its recorded timing.
+	Realtime bool
+
+	mu     sync.Mutex
+	its    []*Interaction
+	used   []bool
+	byKey  map[string][]int
+	byPath map[string][]int
+}
+
+// NewReplayTransport loads every interaction in dir.
+func NewReplayTransport(dir string) (*ReplayTransport, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	t := &ReplayTransport{byKey: map[string][]int{}, byPath: map[string][]int{}}
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		var it Interaction
+		if err := json.Unmarshal(b, &it); err != nil {
+			return nil, fmt.Errorf("%s: %w", f, err)
+		}
+		u, err := url.Parse(it.URL)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f, err)
+		}
+		i := len(t.its)
+		t.its = append(t.its, &it)
+		key, path := replayKey(it.Method, u, it.RequestBody), it.Method+" "+u.Path
+		t.byKey[key] = append(t.byKey[key], i)
+		t.byPath[path] = append(t.byPath[path], i)
+	}
+	if len(t.its) == 0 {
+		return nil, fmt.Errorf("replay: no interactions in %s", dir)
+	}
+	t.used = make([]bool, len(t.its))
+	return t, nil
+}
+
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = redactBody(req.Header.Get("Content-Type"), b)
+	}
+	it := t.next(req.Method, req.URL, body)
+	if it == nil {
+		return nil, fmt.Errorf("replay: no recorded response for %s %s", req.Method, redactURL(req.URL))
+	}
+	resp := &http.Response{
+		StatusCode: it.Status,
+		Status:     fmt.Sprintf("%d %s", it.Status, http.StatusText(it.Status)),
+		Proto:      "HTTP/1.1", ProtoMajor: 1, ProtoMinor: 1,
+		Header:  it.Header.Clone(),
+		Request: req,
+	}
+	if resp.Header == nil {
+		resp.Header = http.Header{}
+	}
+	// Redaction may have changed the body length.
+	resp.Header.Del("Content-Length")
+	if !t.Realtime {
+		var buf bytes.Buffer
+		for _, c := range it.Chunks {
+			buf.Write(c.Data)
+		}
+		resp.Body = io.NopCloser(&buf)
+		resp.ContentLength = int64(buf.Len())
+		return resp, nil
+	}
+	pr, pw := io.Pipe()
+	ctx := req.Context()
+	go func() {
+		start := time.Now()
+		for _, c := range it.Chunks {
+			if d := time.Duration(c.ElapsedMS)*time.Millisecond - time.Since(start); d > 0 {
+				select {
+				case <-time.After(d):
+				case <-ctx.Done():
+					pw.CloseWithError(ctx.Err())
+					return
+				}
+			}
+			if _, err := pw.Write(c.Data); err != nil {
+				return
+			}
+		}
+		_ = pw.Close()
+	}()
+	resp.Body = pr
+	resp.ContentLength = -1
+	return resp, nil
+}
+
+// next picks the interaction for a request and marks it used. Once all
+// matches are used the last one is repeated.
+func (t *ReplayTransport) next(method string, u *url.URL, body []byte) *Interaction {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	candidates := [][]int{t.byKey[replayKey(method, u, body)], t.byPath[method+" "+u.Path]}
+	for _, idx := range candidates {
+		for _, i := range idx {
+			if !t.used[i] {
+				t.used[i] = true
+				return t.its[i]
+			}
+		}
+	}
+	for _, idx := range candidates {
+		if len(idx) > 0 {
+			return t.its[idx[len(idx)-1]]
+		}
+	}
+	return nil
+}
+
+// replayKey identifies a request by method, path, redacted query and body.
+func replayKey(method string, u *url.URL, body []byte) string {
+	q := u.Query()
+	for _, k := range redactQuery {
+		q.Del(k)
+	}
+	return method + " " + u.Path + "?" + q.Encode() + "\n" + string(body)
+}
+
+func redactURL(u *url.URL) string {
+	c := *u
+	q := c.Query()
+	for _, k := range redactQuery {
+		if q.Has(k) {
+			q.Set(k, redacted)
+		}
+	}
+	c.RawQuery = q.Encode()
+	return c.String()
+}
+
+func redactHeaders(h http.Header) http.Header {
+	out := h.Clone()
+	for _, k := range redactHeader {
+		if out.Get(k) != "" {
+			out.Set(k, redacted)
+		}
+	}
+	return out
+}
+
+func redactBody(contentType string, b []byte) []byte {
+	if !strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		return b
+	}
+	q, err := url.ParseQuery(string(b))
+	if err != nil {
+		return b
+	}
+	for _, k := range redactForm {
+		if q.Has(k) {
+			q.Set(k, redacted)
+		}
+	}
+	return []byte(q.Encode())
+}
+
+// cassetteName is a readable file name part for a request: the RPC name or
+// the last path element.
+func cassetteName(u *url.URL) string {
+	name := strings.TrimSuffix(filepath.Base(u.Path), ".json")
+	var b strings.Builder
+	for _, r := range name {
+		if r == '-' || r == '_' || isAlnum(string(r)) {
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() == 0 {
+		return "request"
+	}
+	return b.String()
+}
@@ -0,0 +1,73 @@
+package flightradar
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCassetteRedactsAndReplays(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "secret-session"})
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"success":true,"userData":{"accessToken":"secret-token"}}`)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	rec, err := NewRecordingTransport(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hc := &http.Client{Transport: rec}
+	form := url.Values{"email": {"me@example.com"}, "password": {"secret-password"}}
+	req, _ := http.NewRequest("POST", srv.URL+"/user/login?token=secret-key", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Cookie", "session=secret-session")
+	req.Header.Set("Authorization", "Bearer secret-token")
+	resp, err := hc.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("got %d cassette files, want 1", len(files))
+	}
+	raw, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Bodies are base64 in the cassette, so check their decoded form too.
+	rp, err := NewReplayTransport(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	it := rp.its[0]
+	for _, b := range [][]byte{raw, it.RequestBody, it.Chunks[0].Data} {
+		for _, secret := range []string{"secret", "me@example.com"} {
+			if bytes.Contains(b, []byte(secret)) {
+				t.Errorf("cassette contains %q: %s", secret, b)
+			}
+		}
+	}
+
+	req, _ = http.NewRequest("POST", srv.URL+"/user/login?token=other", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err = (&http.Client{Transport: rp}).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), `"accessToken":"REDACTED"`) {
+		t.Errorf("replayed body = %s", body)
+	}
+}
@@ -2,7 +2,9 @@ package flightradar
 
 import (
 	"context"
+	"log"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -19,14 +21,28 @@ type Client struct {
 	deviceID string
 	// authToken (Bearer) for gRPC-web endpoints when logged in with username/password.
 	authToken string
+	// grpcAcceptEncoding lists encodings advertised for gRPC-web responses.
+	grpcAcceptEncoding []string
+	// cache, when set, serves historic calls from disk (see WithCache).
+	cache *FR24Cache
+	// endpoints are the base URLs of the gRPC-web, JSON API and web hosts.
+	endpoints Endpoints
+	// retry is the policy for transient failures (see WithRetry).
+	retry RetryPolicy
+	// limiters hold the token bucket of each endpoint family (see
+	// WithRateLimits); a missing family is unlimited.
+	limiters map[string]*limiter
+	// debug, when set, receives diagnostics such as rate limit waits.
+	debug *log.Logger
 }
 
 // New creates a Client with sane defaults and a short timeout.
 func New() *Client {
 	return &Client{
-		http:     &http.Client{Timeout: 20 * time.Second},
-		headers:  http.Header(defaultJSONHeaders("")),
-		deviceID: newDeviceID(),
+		http:      &http.Client{Timeout: 20 * time.Second},
+		headers:   http.Header(defaultJSONHeaders("")),
+		deviceID:  newDeviceID(),
+		endpoints: DefaultEndpoints,
 	}
 }
 
@@ -53,12 +69,18 @@ func (c *Client) WithDeviceID(id string) *Client {
 	return c
 }
 
-// do executes a request with base headers and context.
+// do executes a request with base headers and context, retrying transient
+// failures of idempotent requests per the retry policy.
 func (c *Client) do(ctx context.Context, req *http.Request) (*http.Response, error) {
 	// ensure a context
 	if ctx == nil {
 		ctx = context.Background()
 	}
+	return c.doRetry(ctx, c.prepare(ctx, req))
+}
+
+// prepare returns req bound to ctx with the client's base headers.
+func (c *Client) prepare(ctx context.Context, req *http.Request) *http.Request {
 	req = req.WithContext(ctx)
 	// merge headers
 	for k, vals := range c.headers {
@@ -72,5 +94,18 @@ func (c *Client) do(ctx context.Context, req *http.Request) (*http.Response, err
 	if req.Header.Get("fr24-device-id") == "" {
 		req.Header.Set("fr24-device-id", c.deviceID)
 	}
+	return req
+}
+
+// send waits for the rate limit of the request's endpoint family and makes a
+// single attempt.
+func (c *Client) send(ctx context.Context, req *http.Request) (*http.Response, error) {
+	family := familyAPI
+	if strings.HasPrefix(req.Header.Get("Content-Type"), "application/grpc-web") {
+		family = familyGRPC
+	}
+	if err := c.wait(ctx, family); err != nil {
+		return nil, err
+	}
 	return c.http.Do(req)
 }
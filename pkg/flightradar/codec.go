@@ -0,0 +1,91 @@
+package flightradar
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Codec decompresses gRPC message payloads sent with a given grpc-encoding.
+// Additional codecs (e.g. snappy) can be plugged in with RegisterCodec.
+type Codec interface {
+	// Name is the grpc-encoding value, e.g. "gzip".
+	Name() string
+	Decompress(data []byte) ([]byte, error)
+}
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = map[string]Codec{}
+)
+
+func init() {
+	RegisterCodec(gzipCodec{})
+	RegisterCodec(deflateCodec{})
+}
+
+// RegisterCodec makes a codec available for decoding compressed frames. It
+// replaces any codec previously registered under the same name.
+func RegisterCodec(c Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	codecs[strings.ToLower(c.Name())] = c
+}
+
+// CodecFor returns the codec registered for a grpc-encoding value.
+func CodecFor(name string) (Codec, bool) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	c, ok := codecs[strings.ToLower(strings.TrimSpace(name))]
+	return c, ok
+}
+
+// decompress inflates a compressed message payload. When the encoding is
+// unknown (e.g. the grpc-encoding header was not available to the caller) the
+// payload is sniffed for gzip and zlib headers.
+func decompress(encoding string, data []byte) ([]byte, error) {
+	if encoding == "" || encoding == "identity" {
+		switch {
+		case len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b:
+			encoding = "gzip"
+		case len(data) >= 2 && data[0]&0x0f == 8 && (uint16(data[0])<<8|uint16(data[1]))%31 == 0:
+			encoding = "deflate"
+		default:
+			return nil, fmt.Errorf("compressed message without known grpc-encoding")
+		}
+	}
+	c, ok := CodecFor(encoding)
+	if !ok {
+		return nil, fmt.Errorf("unsupported grpc-encoding %q", encoding)
+	}
+	return c.Decompress(data)
+}
+
+type gzipCodec struct{}
+
+func (gzipCodec) Name() string { return "gzip" }
+func (gzipCodec) Decompress(data []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = zr.Close() }()
+	return io.ReadAll(zr)
+}
+
+// deflateCodec follows grpc-go, where "deflate" is the zlib format.
+type deflateCodec struct{}
+
+func (deflateCodec) Name() string { return "deflate" }
+func (deflateCodec) Decompress(data []byte) ([]byte, error) {
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = zr.Close() }()
+	return io.ReadAll(zr)
+}
@@ -0,0 +1,93 @@
+package flightradar
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"testing"
+)
+
+func gzipBytes(t *testing.T, b []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func zlibBytes(t *testing.T, b []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	if _, err := zw.Write(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecompress(t *testing.T) {
+	plain := []byte("hello frames")
+	tests := []struct {
+		name     string
+		encoding string
+		data     []byte
+	}{
+		{"gzip", "gzip", gzipBytes(t, plain)},
+		{"deflate", "deflate", zlibBytes(t, plain)},
+		{"case and spaces", " GZIP ", gzipBytes(t, plain)},
+		{"sniff gzip", "", gzipBytes(t, plain)},
+		{"sniff deflate", "identity", zlibBytes(t, plain)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decompress(tt.encoding, tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, plain) {
+				t.Fatalf("got %q, want %q", got, plain)
+			}
+		})
+	}
+}
+
+func TestDecompressErrors(t *testing.T) {
+	if _, err := decompress("", []byte("not compressed")); err == nil {
+		t.Error("sniffing plain bytes: expected error")
+	}
+	if _, err := decompress("snappy", []byte{1, 2, 3}); err == nil {
+		t.Error("unknown encoding: expected error")
+	}
+	if _, err := decompress("gzip", zlibBytes(t, []byte("x"))); err == nil {
+		t.Error("zlib data as gzip: expected error")
+	}
+}
+
+type upperCodec struct{}
+
+func (upperCodec) Name() string { return "Upper" }
+func (upperCodec) Decompress(data []byte) ([]byte, error) {
+	return bytes.ToUpper(data), nil
+}
+
+func TestRegisterCodec(t *testing.T) {
+	RegisterCodec(upperCodec{})
+	if _, ok := CodecFor("upper"); !ok {
+		t.Fatal("registered codec not found")
+	}
+	got, err := decompress("upper", []byte("abc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "ABC" {
+		t.Fatalf("got %q", got)
+	}
+}
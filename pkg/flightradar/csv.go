@@ -2,12 +2,16 @@ package flightradar
 
 import (
 	"encoding/csv"
+	"errors"
+	"fmt"
 	"io"
 	"reflect"
 	"strconv"
 )
 
 // WriteCSV writes a slice of structs to CSV with header inferred from `csv` tags or field names.
+// Fields tagged `csv:"-"` are skipped and nested structs (or pointers to
+// structs) are flattened into "parent.child" columns.
 func WriteCSV(w io.Writer, slice any) error {
 	rv := reflect.ValueOf(slice)
 	if rv.Kind() != reflect.Slice {
@@ -20,24 +24,10 @@ func WriteCSV(w io.Writer, slice any) error {
 	if rv.Len() == 0 {
 		return nil
 	}
-	t := rv.Index(0).Type()
-	headers := make([]string, 0, t.NumField())
-	fields := make([]int, 0, t.NumField())
-	for i := 0; i < t.NumField(); i++ {
-		f := t.Field(i)
-		if f.PkgPath != "" {
-			continue
-		} // unexported
-		tag := f.Tag.Get("csv")
-		name := tag
-		if name == "" || name == "-" {
-			name = f.Name
-		}
-		if name == "-" {
-			continue
-		}
-		headers = append(headers, name)
-		fields = append(fields, i)
+	cols := csvColumns(rv.Index(0).Type(), "", nil)
+	headers := make([]string, 0, len(cols))
+	for _, col := range cols {
+		headers = append(headers, col.name)
 	}
 	if err := cw.Write(headers); err != nil {
 		return err
@@ -45,10 +35,9 @@ func WriteCSV(w io.Writer, slice any) error {
 
 	for i := 0; i < rv.Len(); i++ {
 		rowv := rv.Index(i)
-		rec := make([]string, 0, len(fields))
-		for _, idx := range fields {
-			fv := rowv.Field(idx)
-			rec = append(rec, toString(fv))
+		rec := make([]string, 0, len(cols))
+		for _, col := range cols {
+			rec = append(rec, toString(fieldByPath(rowv, col.index, false)))
 		}
 		if err := cw.Write(rec); err != nil {
 			return err
@@ -57,6 +46,107 @@ func WriteCSV(w io.Writer, slice any) error {
 	return cw.Error()
 }
 
+// ReadCSV reads CSV written by WriteCSV into out, which must be a pointer to
+// a slice of structs. Columns are matched by header name; unknown columns are
+// ignored and empty cells leave pointer fields nil.
+func ReadCSV(r io.Reader, out any) error {
+	pv := reflect.ValueOf(out)
+	if pv.Kind() != reflect.Pointer || pv.Elem().Kind() != reflect.Slice {
+		return errors.New("csv: out must be a pointer to a slice")
+	}
+	sv := pv.Elem()
+	et := sv.Type().Elem()
+	if et.Kind() != reflect.Struct {
+		return errors.New("csv: slice element must be a struct")
+	}
+	cr := csv.NewReader(r)
+	headers, err := cr.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	byName := map[string][]int{}
+	for _, col := range csvColumns(et, "", nil) {
+		byName[col.name] = col.index
+	}
+	paths := make([][]int, len(headers))
+	for i, h := range headers {
+		paths[i] = byName[h]
+	}
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		elem := reflect.New(et).Elem()
+		for i, cell := range row {
+			if i >= len(paths) || paths[i] == nil || cell == "" {
+				continue
+			}
+			if err := fromString(fieldByPath(elem, paths[i], true), cell); err != nil {
+				return fmt.Errorf("csv: column %q: %w", headers[i], err)
+			}
+		}
+		sv.Set(reflect.Append(sv, elem))
+	}
+}
+
+type csvColumn struct {
+	name  string
+	index []int
+}
+
+func csvColumns(t reflect.Type, prefix string, index []int) []csvColumn {
+	var cols []csvColumn
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		} // unexported
+		name := f.Tag.Get("csv")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		idx := append(append([]int(nil), index...), i)
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct {
+			cols = append(cols, csvColumns(ft, prefix+name+".", idx)...)
+			continue
+		}
+		cols = append(cols, csvColumn{name: prefix + name, index: idx})
+	}
+	return cols
+}
+
+// fieldByPath walks a field index path through nested structs. Nil pointers
+// yield an invalid value unless alloc is set, in which case they are allocated.
+func fieldByPath(v reflect.Value, index []int, alloc bool) reflect.Value {
+	for i, idx := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(idx)
+	}
+	return v
+}
+
 func toString(v reflect.Value) string {
 	if !v.IsValid() {
 		return ""
@@ -86,6 +176,46 @@ func toString(v reflect.Value) string {
 	}
 }
 
+func fromString(v reflect.Value, s string) error {
+	if v.Kind() == reflect.Pointer {
+		p := reflect.New(v.Type().Elem())
+		if err := fromString(p.Elem(), s); err != nil {
+			return err
+		}
+		v.Set(p)
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	}
+	return nil
+}
+
 func itoa(n int64) string   { return strconv.FormatInt(n, 10) }
 func uitoa(n uint64) string { return strconv.FormatUint(n, 10) }
 func ftoa(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
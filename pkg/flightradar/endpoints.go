@@ -0,0 +1,63 @@
+package flightradar
+
+import (
+	"os"
+	"strings"
+)
+
+// Endpoints are the base URLs the client talks to, without a trailing slash.
+// Point them at a mock server or a caching proxy to redirect traffic.
+type Endpoints struct {
+	// GRPC serves the gRPC-web feed API.
+	GRPC string `json:"grpc"`
+	// API serves the common/v1 JSON endpoints (flight list, airport, playback).
+	API string `json:"api"`
+	// Web serves search and login.
+	Web string `json:"web"`
+}
+
+// DefaultEndpoints are the Flightradar24 production hosts.
+var DefaultEndpoints = Endpoints{
+	GRPC: "https://data-feed.flightradar24.com",
+	API:  "https://api.flightradar24.com",
+	Web:  "https://www.flightradar24.com",
+}
+
+// WithEndpoints sets the base URLs. Empty fields keep the current value.
+func (c *Client) WithEndpoints(e Endpoints) *Client {
+	if e.GRPC != "" {
+		c.endpoints.GRPC = strings.TrimRight(e.GRPC, "/")
+	}
+	if e.API != "" {
+		c.endpoints.API = strings.TrimRight(e.API, "/")
+	}
+	if e.Web != "" {
+		c.endpoints.Web = strings.TrimRight(e.Web, "/")
+	}
+	return c
+}
+
+// Endpoints returns the base URLs in use.
+func (c *Client) Endpoints() Endpoints { return c.endpoints }
+
+// EndpointsFromEnvOrConfig reads endpoint overrides from the environment
+// (fr24_grpc_url, fr24_api_url, fr24_web_url) or the [global] section of the
+// config file (grpc_url, api_url, web_url); the environment wins. Unset
+// fields are empty, so the result can be passed to WithEndpoints as is.
+func EndpointsFromEnvOrConfig() Endpoints {
+	cfg := readConfig()
+	return Endpoints{
+		GRPC: envOrConfig(cfg, "grpc_url"),
+		API:  envOrConfig(cfg, "api_url"),
+		Web:  envOrConfig(cfg, "web_url"),
+	}
+}
+
+// envOrConfig returns the fr24_<key> environment variable, falling back to
+// key in the config file.
+func envOrConfig(cfg map[string]string, key string) string {
+	if v := os.Getenv("fr24_" + key); v != "" {
+		return v
+	}
+	return cfg[key]
+}
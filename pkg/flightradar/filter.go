@@ -0,0 +1,286 @@
+package flightradar
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/igolaizola/fr24/pkg/proto"
+)
+
+// Interval is an inclusive [Min, Max] range used by altitude, speed and
+// birth-year filters.
+type Interval struct {
+	Min int32
+	Max int32
+}
+
+// AirlineFilter matches flights by airline ICAO designator (e.g. "CPA"),
+// either by livery (painted as) or by operator.
+type AirlineFilter struct {
+	ICAO string
+	Type pb.AirlineFilterType
+}
+
+// AirportFilter matches flights by airport IATA code or country. Direction
+// selects inbound, outbound or both.
+type AirportFilter struct {
+	IATA      string
+	CountryID int32
+	Direction pb.AirportFilterType
+}
+
+// LiveFeedFilter maps to pb.Filter, the server-side filter of LiveFeed and
+// Playback requests. The zero value filters nothing.
+type LiveFeedFilter struct {
+	Altitudes    []Interval // feet
+	Speeds       []Interval // ground speed, knots
+	Airlines     []AirlineFilter
+	Callsigns    []string
+	Radars       []string // receiver ids, e.g. "T-VHST000"
+	Regs         []string
+	Airports     []AirportFilter
+	Flights      []string
+	Types        []string // aircraft types, wildcards allowed, e.g. "B74*"
+	BirthYears   []Interval
+	Squawks      []uint32 // base-10 value of the octal code, see ParseSquawk
+	Origins      []AirportFilter
+	Destinations []AirportFilter
+	Categories   []pb.Service
+	Airspaces    []string
+}
+
+// IsZero reports whether the filter has no criteria.
+func (f LiveFeedFilter) IsZero() bool {
+	return len(f.Altitudes) == 0 && len(f.Speeds) == 0 && len(f.Airlines) == 0 &&
+		len(f.Callsigns) == 0 && len(f.Radars) == 0 && len(f.Regs) == 0 &&
+		len(f.Airports) == 0 && len(f.Flights) == 0 && len(f.Types) == 0 &&
+		len(f.BirthYears) == 0 && len(f.Squawks) == 0 && len(f.Origins) == 0 &&
+		len(f.Destinations) == 0 && len(f.Categories) == 0 && len(f.Airspaces) == 0
+}
+
+// Validate checks ranges, codes and enum values.
+func (f LiveFeedFilter) Validate() error {
+	for _, iv := range f.Altitudes {
+		if err := iv.validate("altitude", -2000, 100000); err != nil {
+			return err
+		}
+	}
+	for _, iv := range f.Speeds {
+		if err := iv.validate("speed", 0, 5000); err != nil {
+			return err
+		}
+	}
+	for _, iv := range f.BirthYears {
+		if err := iv.validate("birth year", 1900, int32(time.Now().Year())); err != nil {
+			return err
+		}
+	}
+	for _, a := range f.Airlines {
+		if !isAlnum(a.ICAO) || len(a.ICAO) != 3 {
+			return fmt.Errorf("invalid airline ICAO designator %q", a.ICAO)
+		}
+		if _, ok := pb.AirlineFilterType_name[int32(a.Type)]; !ok {
+			return fmt.Errorf("invalid airline filter type %d", a.Type)
+		}
+	}
+	for _, list := range [][]AirportFilter{f.Airports, f.Origins, f.Destinations} {
+		for _, a := range list {
+			if a.IATA == "" && a.CountryID == 0 {
+				return fmt.Errorf("airport filter needs an IATA code or country id")
+			}
+			if a.IATA != "" && (!isAlnum(a.IATA) || len(a.IATA) != 3) {
+				return fmt.Errorf("invalid airport IATA code %q", a.IATA)
+			}
+			if _, ok := pb.AirportFilterType_name[int32(a.Direction)]; !ok {
+				return fmt.Errorf("invalid airport filter direction %d", a.Direction)
+			}
+		}
+	}
+	for _, t := range f.Types {
+		if t == "" || !isAlnum(strings.ReplaceAll(t, "*", "")) {
+			return fmt.Errorf("invalid aircraft type %q", t)
+		}
+	}
+	for _, sq := range f.Squawks {
+		if sq > 0o7777 {
+			return fmt.Errorf("invalid squawk %o", sq)
+		}
+	}
+	for _, c := range f.Categories {
+		if _, ok := pb.Service_name[int32(c)]; !ok {
+			return fmt.Errorf("invalid category %d", c)
+		}
+	}
+	for name, list := range map[string][]string{"callsign": f.Callsigns, "radar": f.Radars, "registration": f.Regs, "flight": f.Flights, "airspace": f.Airspaces} {
+		for _, v := range list {
+			if strings.TrimSpace(v) == "" {
+				return fmt.Errorf("empty %s filter", name)
+			}
+		}
+	}
+	return nil
+}
+
+func (iv Interval) validate(name string, lo, hi int32) error {
+	if iv.Min > iv.Max {
+		return fmt.Errorf("invalid %s range %d-%d: min greater than max", name, iv.Min, iv.Max)
+	}
+	if iv.Min < lo || iv.Max > hi {
+		return fmt.Errorf("invalid %s range %d-%d: must be within %d-%d", name, iv.Min, iv.Max, lo, hi)
+	}
+	return nil
+}
+
+func (f LiveFeedFilter) toProto() *pb.Filter {
+	if f.IsZero() {
+		return nil
+	}
+	intervals := func(ivs []Interval) []*pb.Interval {
+		out := make([]*pb.Interval, 0, len(ivs))
+		for _, iv := range ivs {
+			out = append(out, &pb.Interval{Min: iv.Min, Max: iv.Max})
+		}
+		return out
+	}
+	airports := func(afs []AirportFilter) []*pb.AirportFilter {
+		out := make([]*pb.AirportFilter, 0, len(afs))
+		for _, a := range afs {
+			out = append(out, &pb.AirportFilter{Iata: strings.ToUpper(a.IATA), CountryId: a.CountryID, Type: a.Direction})
+		}
+		return out
+	}
+	airlines := make([]*pb.AirlineFilter, 0, len(f.Airlines))
+	for _, a := range f.Airlines {
+		airlines = append(airlines, &pb.AirlineFilter{Icao: strings.ToUpper(a.ICAO), Type: a.Type})
+	}
+	return &pb.Filter{
+		AltitudeRangesList:  intervals(f.Altitudes),
+		SpeedRangesList:     intervals(f.Speeds),
+		AirlinesList:        airlines,
+		CallsignsList:       f.Callsigns,
+		RadarsList:          f.Radars,
+		RegsList:            f.Regs,
+		AirportsList:        airports(f.Airports),
+		FlightsList:         f.Flights,
+		TypesList:           f.Types,
+		BirthYearRangesList: intervals(f.BirthYears),
+		SquawksList:         f.Squawks,
+		OriginsList:         airports(f.Origins),
+		DestinationsList:    airports(f.Destinations),
+		CategoriesList:      f.Categories,
+		AirspacesList:       f.Airspaces,
+	}
+}
+
+// ParseInterval parses "min-max" or a single value "n" (min = max = n).
+func ParseInterval(s string) (Interval, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Interval{}, fmt.Errorf("empty range")
+	}
+	// skip the first byte so a negative min (e.g. "-1000-5000") still parses
+	i := strings.Index(s[1:], "-")
+	if i < 0 {
+		n, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return Interval{}, fmt.Errorf("invalid range %q", s)
+		}
+		return Interval{Min: int32(n), Max: int32(n)}, nil
+	}
+	lo, err1 := strconv.ParseInt(strings.TrimSpace(s[:i+1]), 10, 32)
+	hi, err2 := strconv.ParseInt(strings.TrimSpace(s[i+2:]), 10, 32)
+	if err1 != nil || err2 != nil {
+		return Interval{}, fmt.Errorf("invalid range %q", s)
+	}
+	return Interval{Min: int32(lo), Max: int32(hi)}, nil
+}
+
+// ParseAirlineFilter parses "CPA" (painted as) or "CPA:operated" (operated by).
+func ParseAirlineFilter(s string) (AirlineFilter, error) {
+	code, kind, _ := strings.Cut(strings.TrimSpace(s), ":")
+	af := AirlineFilter{ICAO: strings.ToUpper(code)}
+	switch strings.ToLower(kind) {
+	case "", "painted", "painted_as":
+		af.Type = pb.AirlineFilterType_PAINTED_AS
+	case "operated", "operated_by":
+		af.Type = pb.AirlineFilterType_OPERATED_BY
+	default:
+		return AirlineFilter{}, fmt.Errorf("invalid airline filter %q: want CODE[:painted|:operated]", s)
+	}
+	return af, nil
+}
+
+// ParseAirportFilter parses "HKG", "HKG:inbound" or "HKG:outbound". A numeric
+// code is taken as a country id.
+func ParseAirportFilter(s string) (AirportFilter, error) {
+	code, dir, _ := strings.Cut(strings.TrimSpace(s), ":")
+	var af AirportFilter
+	if n, err := strconv.ParseInt(code, 10, 32); err == nil {
+		af.CountryID = int32(n)
+	} else {
+		af.IATA = strings.ToUpper(code)
+	}
+	switch strings.ToLower(dir) {
+	case "", "both":
+		af.Direction = pb.AirportFilterType_BOTH
+	case "inbound", "in":
+		af.Direction = pb.AirportFilterType_INBOUND
+	case "outbound", "out":
+		af.Direction = pb.AirportFilterType_OUTBOUND
+	default:
+		return AirportFilter{}, fmt.Errorf("invalid airport filter %q: want CODE[:inbound|:outbound]", s)
+	}
+	return af, nil
+}
+
+// ParseSquawk parses an octal squawk code such as "7700" into the base-10
+// value used by the API.
+func ParseSquawk(s string) (uint32, error) {
+	s = strings.TrimSpace(s)
+	n, err := strconv.ParseUint(s, 8, 32)
+	if err != nil || len(s) > 4 {
+		return 0, fmt.Errorf("invalid squawk %q", s)
+	}
+	return uint32(n), nil
+}
+
+// ParseService parses a service category name (e.g. "cargo", "military",
+// "business_jets") or its enum name.
+func ParseService(s string) (pb.Service, error) {
+	key := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(s), "-", "_"))
+	switch key {
+	case "MILITARY", "GOVERNMENT":
+		return pb.Service_MILITARY_AND_GOVERNMENT, nil
+	case "BUSINESS", "BIZJET", "BIZJETS":
+		return pb.Service_BUSINESS_JETS, nil
+	case "GA":
+		return pb.Service_GENERAL_AVIATION, nil
+	case "HELICOPTER":
+		return pb.Service_HELICOPTERS, nil
+	case "GLIDER":
+		return pb.Service_GLIDERS, nil
+	case "DRONE":
+		return pb.Service_DRONES, nil
+	case "GROUND":
+		return pb.Service_GROUND_VEHICLES, nil
+	case "OTHER":
+		return pb.Service_OTHER_SERVICE, nil
+	case "UNCATEGORIZED":
+		return pb.Service_NON_CATEGORIZED, nil
+	}
+	if v, ok := pb.Service_value[key]; ok {
+		return pb.Service(v), nil
+	}
+	return 0, fmt.Errorf("unknown category %q", s)
+}
+
+func isAlnum(s string) bool {
+	for _, r := range s {
+		if (r < '0' || r > '9') && (r < 'A' || r > 'Z') && (r < 'a' || r > 'z') {
+			return false
+		}
+	}
+	return true
+}
@@ -158,3 +158,98 @@ func TopFlightToRecord(ff *pb.FollowedFlight) TopFlightRecord {
 		FullDesc:     ff.GetFullDescription(),
 	}
 }
+
+// Follow flight flattener; one record per stream frame.
+func FollowFlightToRecord(resp *pb.FollowFlightResponse) FollowFlightRecord {
+	ai := resp.GetAircraftInfo()
+	fp := resp.GetFlightPlan()
+	si := resp.GetScheduleInfo()
+	pr := resp.GetFlightProgress()
+	fi := resp.GetFlightInfo()
+	trail := make([]TrailPointRecord, 0, len(resp.GetFlightTrailList()))
+	for _, tp := range resp.GetFlightTrailList() {
+		trail = append(trail, TrailPointRecord{
+			Timestamp:     tp.GetSnapshotId(),
+			Latitude:      tp.GetLat(),
+			Longitude:     tp.GetLon(),
+			Altitude:      tp.GetAltitude(),
+			GroundSpeed:   tp.GetSpd(),
+			Track:         tp.GetHeading(),
+			VerticalSpeed: tp.GetVspd(),
+		})
+	}
+	return FollowFlightRecord{
+		TimestampMS:       fi.GetTimestampMs(),
+		FlightID:          fi.GetFlightid(),
+		Latitude:          fi.GetLat(),
+		Longitude:         fi.GetLon(),
+		Track:             fi.GetTrack(),
+		Altitude:          fi.GetAlt(),
+		GroundSpeed:       fi.GetSpeed(),
+		VerticalSpeed:     fi.GetVspeed(),
+		OnGround:          fi.GetOnGround(),
+		Callsign:          fi.GetCallsign(),
+		Squawk:            fi.GetSquawk(),
+		Source:            fi.GetSource(),
+		ICAOAddress:       ai.GetIcaoAddress(),
+		Reg:               ai.GetReg(),
+		Typecode:          ai.GetType(),
+		FlightNumber:      si.GetFlightNumber(),
+		Origin:            fp.GetDeparture(),
+		Destination:       fp.GetDestination(),
+		FlightStage:       pr.GetFlightStage(),
+		DelayStatus:       pr.GetDelayStatus(),
+		ProgressPct:       pr.GetProgressPct(),
+		TraversedDistance: pr.GetTraversedDistance(),
+		RemainingDistance: pr.GetRemainingDistance(),
+		ElapsedTime:       pr.GetElapsedTime(),
+		RemainingTime:     pr.GetRemainingTime(),
+		ETA:               pr.GetEta(),
+		Trail:             trail,
+	}
+}
+
+// Trail flatteners (LiveTrail, HistoricTrail)
+func RadarHistoryToRecord(r *pb.RadarHistoryRecord) TrailRecord {
+	return TrailRecord{
+		Timestamp:     r.GetTimestamp(),
+		Latitude:      r.GetLat(),
+		Longitude:     r.GetLon(),
+		Altitude:      r.GetAltitude(),
+		GroundSpeed:   r.GetSpd(),
+		Track:         r.GetHeading(),
+		VerticalSpeed: r.GetVspd(),
+		Squawk:        r.GetSquawk(),
+		Source:        r.GetSource(),
+		Callsign:      r.GetCallsign(),
+	}
+}
+
+func TrailToRecords(list []*pb.RadarHistoryRecord) []TrailRecord {
+	out := make([]TrailRecord, 0, len(list))
+	for _, r := range list {
+		out = append(out, RadarHistoryToRecord(r))
+	}
+	return out
+}
+
+// Search index flattener
+func SearchIndexToRecords(resp *pb.FetchSearchIndexResponse) []SearchIndexRecord {
+	out := make([]SearchIndexRecord, 0, len(resp.GetFlightsList()))
+	for _, f := range resp.GetFlightsList() {
+		out = append(out, SearchIndexRecord{
+			FlightID:     f.GetId(),
+			Callsign:     f.GetCallsign(),
+			Flight:       f.GetFlight(),
+			Registration: f.GetReg(),
+			Typecode:     f.GetAcType(),
+			Operator:     f.GetOperator(),
+			Origin:       f.GetSchdFrom(),
+			Destination:  f.GetSchdTo(),
+			Latitude:     f.GetLat(),
+			Longitude:    f.GetLon(),
+			Restricted:   f.GetRestricted(),
+		})
+	}
+	return out
+}
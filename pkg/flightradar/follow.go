@@ -0,0 +1,213 @@
+package flightradar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	pb "github.com/igolaizola/fr24/pkg/proto"
+)
+
+// FollowFlightOptions configures a reconnecting FollowFlight subscription.
+type FollowFlightOptions struct {
+	Restriction pb.RestrictionVisibility
+	// MinBackoff is the first reconnect delay (default 1s). It doubles after
+	// every failed attempt up to MaxBackoff (default 1m) and resets once a
+	// frame is received.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// MaxRetries stops the subscription after N consecutive failed
+	// reconnects. 0 retries forever.
+	MaxRetries int
+}
+
+// FollowFlightSubscription follows a flight across network drops. Frames are
+// delivered on C; transient errors and terminal gRPC trailers are delivered on
+// Errors. Both channels are closed when the subscription ends, which happens
+// when the flight lands, the context is cancelled, Close is called, the server
+// returns a non-retryable gRPC status or a 4xx HTTP status other than 429, or
+// MaxRetries is exceeded.
+type FollowFlightSubscription struct {
+	C      <-chan *pb.FollowFlightResponse
+	Errors <-chan error
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	mu     sync.Mutex
+	err    error
+}
+
+// ErrFlightLanded is reported by Err when the subscription ended because the
+// flight reached the ground after arrival.
+var ErrFlightLanded = errors.New("flight landed")
+
+// SubscribeFollowFlight starts a reconnecting FollowFlight subscription.
+// Errors is buffered; if the caller does not drain it, errors beyond the
+// buffer are dropped rather than stalling the stream.
+func (c *Client) SubscribeFollowFlight(ctx context.Context, flightID uint32, opts FollowFlightOptions) *FollowFlightSubscription {
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = time.Second
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = time.Minute
+	}
+	if opts.MaxBackoff < opts.MinBackoff {
+		opts.MaxBackoff = opts.MinBackoff
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	ch := make(chan *pb.FollowFlightResponse, 8)
+	errc := make(chan error, 16)
+	s := &FollowFlightSubscription{C: ch, Errors: errc, cancel: cancel, done: make(chan struct{})}
+	go s.run(ctx, c, flightID, opts, ch, errc)
+	return s
+}
+
+// Close stops the subscription and waits for it to finish.
+func (s *FollowFlightSubscription) Close() {
+	s.cancel()
+	<-s.done
+}
+
+// Err returns why the subscription ended once C is closed: ErrFlightLanded,
+// the terminal error, or nil if it was cancelled by the caller.
+func (s *FollowFlightSubscription) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+func (s *FollowFlightSubscription) run(ctx context.Context, c *Client, flightID uint32, opts FollowFlightOptions, ch chan<- *pb.FollowFlightResponse, errc chan<- error) {
+	defer close(s.done)
+	defer close(errc)
+	defer close(ch)
+	defer s.cancel()
+
+	report := func(err error) {
+		select {
+		case errc <- err:
+		default:
+		}
+	}
+	finish := func(err error) {
+		s.mu.Lock()
+		s.err = err
+		s.mu.Unlock()
+	}
+
+	var airborne bool
+	backoff := opts.MinBackoff
+	failures := 0
+	for {
+		received, err := s.follow(ctx, c, flightID, opts.Restriction, ch, &airborne)
+		if ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, ErrFlightLanded) {
+			finish(err)
+			return
+		}
+		if err != nil {
+			report(err)
+			var ge *GrpcError
+			var he *HTTPError
+			if errors.As(err, &ge) && !retryableGrpcStatus(ge.Status) ||
+				errors.As(err, &he) && !retryableHTTPStatus(he.StatusCode) {
+				finish(err)
+				return
+			}
+		}
+		if received {
+			backoff = opts.MinBackoff
+			failures = 0
+		} else {
+			failures++
+			if opts.MaxRetries > 0 && failures > opts.MaxRetries {
+				if err == nil {
+					err = errors.New("follow flight: stream closed without frames")
+				}
+				finish(fmt.Errorf("follow flight: giving up after %d retries: %w", opts.MaxRetries, err))
+				return
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > opts.MaxBackoff {
+			backoff = opts.MaxBackoff
+		}
+	}
+}
+
+// follow runs a single stream connection until it ends. It reports whether at
+// least one frame was received. A nil error means the server closed the stream
+// cleanly and it is safe to reconnect.
+func (s *FollowFlightSubscription) follow(ctx context.Context, c *Client, flightID uint32, restriction pb.RestrictionVisibility, ch chan<- *pb.FollowFlightResponse, airborne *bool) (bool, error) {
+	resp, err := c.openFollowFlight(ctx, flightID, restriction)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return false, newHTTPError(resp)
+	}
+	received, landed := false, false
+	err = readFollowFlight(resp, func(msg *pb.FollowFlightResponse) bool {
+		received = true
+		select {
+		case ch <- msg:
+		case <-ctx.Done():
+			return false
+		}
+		landed = hasLanded(msg, airborne)
+		return !landed
+	})
+	if landed {
+		return received, ErrFlightLanded
+	}
+	if err == nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	return received, err
+}
+
+// hasLanded reports whether the flight is on the ground after its arrival:
+// either an actual arrival time is set, or it was seen airborne earlier in the
+// subscription.
+func hasLanded(msg *pb.FollowFlightResponse, airborne *bool) bool {
+	stage := msg.GetFlightProgress().GetFlightStage()
+	if stage != pb.FlightStage_ON_GROUND {
+		if stage != pb.FlightStage_UNKNOWN {
+			*airborne = true
+		}
+		return false
+	}
+	return *airborne || msg.GetScheduleInfo().GetActualArrival() != 0
+}
+
+// retryableGrpcStatus reports whether a trailer status may succeed on retry.
+// Client-side mistakes and auth failures are terminal.
+func retryableGrpcStatus(status string) bool {
+	switch status {
+	case "3", // INVALID_ARGUMENT
+		"5",  // NOT_FOUND
+		"7",  // PERMISSION_DENIED
+		"9",  // FAILED_PRECONDITION
+		"12", // UNIMPLEMENTED
+		"16": // UNAUTHENTICATED
+		return false
+	}
+	return true
+}
+
+// retryableHTTPStatus reports whether an HTTP status may succeed on retry.
+// Client errors such as a bad token (401/403) or unknown flight (404) are
+// terminal, except 429 Too Many Requests.
+func retryableHTTPStatus(code int) bool {
+	return code < 400 || code >= 500 || code == http.StatusTooManyRequests
+}
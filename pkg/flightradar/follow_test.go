@@ -0,0 +1,51 @@
+package flightradar
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSubscribeFollowFlightHTTPStatus(t *testing.T) {
+	tests := []struct {
+		status   int
+		terminal bool
+	}{
+		{http.StatusUnauthorized, true},
+		{http.StatusForbidden, true},
+		{http.StatusNotFound, true},
+		{http.StatusTooManyRequests, false},
+		{http.StatusServiceUnavailable, false},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				calls.Add(1)
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+			c := New().WithEndpoints(Endpoints{GRPC: srv.URL})
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			sub := c.SubscribeFollowFlight(ctx, 1, FollowFlightOptions{MinBackoff: time.Millisecond, MaxRetries: 3})
+			for range sub.C {
+			}
+			var he *HTTPError
+			if !errors.As(sub.Err(), &he) || he.StatusCode != tt.status {
+				t.Fatalf("Err() = %v, want HTTP %d", sub.Err(), tt.status)
+			}
+			want := int32(4) // first attempt plus MaxRetries
+			if tt.terminal {
+				want = 1
+			}
+			if got := calls.Load(); got != want {
+				t.Errorf("got %d requests, want %d", got, want)
+			}
+		})
+	}
+}
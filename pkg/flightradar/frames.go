@@ -0,0 +1,196 @@
+package flightradar
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	frameCompressed byte = 0x01
+	frameTrailer    byte = 0x80
+
+	// maxFrameSize guards against allocating huge buffers for corrupt bodies.
+	maxFrameSize = 64 << 20
+)
+
+// ErrNoMessage is returned when a unary response ends without a message frame.
+var ErrNoMessage = errors.New("gRPC-web response has no message frame")
+
+// Frame is a single gRPC-web frame: either a (possibly compressed) message or
+// a trailer block.
+type Frame struct {
+	Flag    byte
+	Payload []byte
+}
+
+func (f Frame) IsTrailer() bool    { return f.Flag&frameTrailer != 0 }
+func (f Frame) IsCompressed() bool { return f.Flag&frameCompressed != 0 }
+
+// Bytes re-encodes the frame with its 5-byte prefix.
+func (f Frame) Bytes() []byte {
+	out := make([]byte, 5, 5+len(f.Payload))
+	out[0] = f.Flag
+	binary.BigEndian.PutUint32(out[1:5], uint32(len(f.Payload)))
+	return append(out, f.Payload...)
+}
+
+// FrameReader reads consecutive gRPC-web frames from a response body.
+type FrameReader struct {
+	r *bufio.Reader
+}
+
+func NewFrameReader(r io.Reader) *FrameReader {
+	return &FrameReader{r: bufio.NewReader(r)}
+}
+
+// Next returns the next frame in order. It returns io.EOF once the body ends
+// on a frame boundary and a *GrpcError if the body is truncated mid-frame.
+func (fr *FrameReader) Next() (Frame, error) {
+	var header [5]byte
+	n, err := io.ReadFull(fr.r, header[:])
+	if err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return Frame{}, &GrpcError{Message: "truncated frame header", Raw: header[:n]}
+		}
+		return Frame{}, err
+	}
+	size := binary.BigEndian.Uint32(header[1:5])
+	if size > maxFrameSize {
+		return Frame{}, &GrpcError{Message: fmt.Sprintf("frame too large: %d bytes", size), Raw: header[:]}
+	}
+	payload := make([]byte, size)
+	if n, err := io.ReadFull(fr.r, payload); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return Frame{}, &GrpcError{Message: fmt.Sprintf("truncated frame: got %d of %d bytes", n, size), Raw: append(header[:], payload[:n]...)}
+		}
+		return Frame{}, err
+	}
+	return Frame{Flag: header[0], Payload: payload}, nil
+}
+
+// decodeUnary reads a unary gRPC-web response: one message frame followed by
+// trailers. header carries the HTTP response headers and may be nil; a
+// grpc-status there marks a trailers-only response.
+func decodeUnary(body io.Reader, header http.Header, into proto.Message) error {
+	if err := headerStatus(header); err != nil {
+		return err
+	}
+	encoding := header.Get("grpc-encoding")
+	fr := NewFrameReader(body)
+	got := false
+	for {
+		f, err := fr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if f.IsTrailer() {
+			if err := trailerStatus(f); err != nil {
+				return err
+			}
+			continue
+		}
+		if got {
+			return fmt.Errorf("%w: more than one message in unary response", ErrUnexpectedFrame)
+		}
+		if err := decodeMessage(f, encoding, into); err != nil {
+			return err
+		}
+		got = true
+	}
+	if !got {
+		return ErrNoMessage
+	}
+	return nil
+}
+
+// decodeMessage unmarshals a message frame, decompressing it first if needed.
+func decodeMessage(f Frame, encoding string, into proto.Message) error {
+	msg := f.Payload
+	if f.IsCompressed() {
+		var err error
+		if msg, err = decompress(encoding, msg); err != nil {
+			return &GrpcError{Message: fmt.Sprintf("failed to decompress message: %v", err), Raw: f.Bytes()}
+		}
+	}
+	if err := proto.Unmarshal(msg, into); err != nil {
+		return &ProtoParseError{Err: fmt.Errorf("failed to parse message: %w", err), Raw: f.Bytes()}
+	}
+	return nil
+}
+
+// trailerStatus returns a *GrpcError for a trailer frame with a non-zero
+// grpc-status and nil for a successful one.
+func trailerStatus(f Frame) error {
+	ge := parseTrailers(f.Payload)
+	ge.Raw = f.Bytes()
+	if ge.Status == "0" {
+		return nil
+	}
+	return ge
+}
+
+// headerStatus checks the HTTP headers of a trailers-only response.
+func headerStatus(h http.Header) error {
+	status := h.Get("grpc-status")
+	if status == "" || status == "0" {
+		return nil
+	}
+	return &GrpcError{
+		Message:       "gRPC errored",
+		Status:        status,
+		StatusMessage: unescapeGrpcMessage(h.Get("grpc-message")),
+	}
+}
+
+// parseTrailers extracts grpc-status and grpc-message from a trailer block.
+func parseTrailers(block []byte) *GrpcError {
+	ge := &GrpcError{Message: "gRPC errored"}
+	for _, ln := range bytes.Split(bytes.TrimSpace(block), []byte{'\n'}) {
+		k, v, ok := bytes.Cut(ln, []byte{':'})
+		if !ok {
+			continue
+		}
+		v = bytes.TrimSpace(v)
+		switch string(bytes.ToLower(bytes.TrimSpace(k))) {
+		case "grpc-status":
+			ge.Status = string(v)
+		case "grpc-message":
+			ge.StatusMessage = unescapeGrpcMessage(string(v))
+		case "grpc-status-details-bin":
+			ge.StatusDetails = v
+		}
+	}
+	return ge
+}
+
+// unescapeGrpcMessage decodes the percent-encoding used by grpc-message.
+func unescapeGrpcMessage(s string) string {
+	if u, err := url.PathUnescape(s); err == nil {
+		return u
+	}
+	return s
+}
+
+// Code returns the numeric grpc-status, or -1 if it is missing or malformed.
+func (e *GrpcError) Code() int {
+	if e == nil {
+		return -1
+	}
+	n, err := strconv.Atoi(e.Status)
+	if err != nil {
+		return -1
+	}
+	return n
+}
@@ -0,0 +1,186 @@
+package flightradar
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	pb "github.com/igolaizola/fr24/pkg/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+func okTrailer() Frame {
+	return Frame{Flag: frameTrailer, Payload: []byte("grpc-status:0\r\ngrpc-message:\r\n")}
+}
+
+func messageFrame(t *testing.T, m proto.Message) Frame {
+	t.Helper()
+	b, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Frame{Payload: b}
+}
+
+func body(frames ...Frame) []byte {
+	var buf bytes.Buffer
+	for _, f := range frames {
+		buf.Write(f.Bytes())
+	}
+	return buf.Bytes()
+}
+
+func TestFrameReader(t *testing.T) {
+	raw := body(Frame{Payload: []byte("abc")}, Frame{Flag: frameCompressed, Payload: []byte{1}}, okTrailer())
+	fr := NewFrameReader(bytes.NewReader(raw))
+	var got []Frame
+	for {
+		f, err := fr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, f)
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %d frames, want 3", len(got))
+	}
+	if string(got[0].Payload) != "abc" || got[0].IsCompressed() || got[0].IsTrailer() {
+		t.Errorf("frame 0 = %+v", got[0])
+	}
+	if !got[1].IsCompressed() {
+		t.Errorf("frame 1 not compressed")
+	}
+	if !got[2].IsTrailer() {
+		t.Errorf("frame 2 not a trailer")
+	}
+	if !bytes.Equal(body(got...), raw) {
+		t.Errorf("re-encoded frames differ from input")
+	}
+}
+
+func TestFrameReaderTruncated(t *testing.T) {
+	raw := Frame{Payload: []byte("abcdef")}.Bytes()
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"mid header", raw[:3]},
+		{"mid payload", raw[:8]},
+		{"empty payload", raw[:5]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFrameReader(bytes.NewReader(tt.data)).Next()
+			var ge *GrpcError
+			if !errors.As(err, &ge) {
+				t.Fatalf("got %v, want *GrpcError", err)
+			}
+			if !bytes.Equal(ge.Raw, tt.data) {
+				t.Errorf("Raw = %x, want %x", ge.Raw, tt.data)
+			}
+		})
+	}
+}
+
+func TestFrameReaderTooLarge(t *testing.T) {
+	header := []byte{0, 0, 0, 0, 0}
+	header[1], header[2], header[3], header[4] = 0x04, 0x00, 0x00, 0x01 // maxFrameSize + 1
+	_, err := NewFrameReader(bytes.NewReader(header)).Next()
+	var ge *GrpcError
+	if !errors.As(err, &ge) {
+		t.Fatalf("got %v, want *GrpcError", err)
+	}
+}
+
+func TestDecodeUnary(t *testing.T) {
+	want := &pb.Pong{C: 42}
+	plain := messageFrame(t, want)
+	tests := []struct {
+		name   string
+		header http.Header
+		frame  Frame
+	}{
+		{"plain", nil, plain},
+		{"gzip", http.Header{"Grpc-Encoding": {"gzip"}}, Frame{Flag: frameCompressed, Payload: gzipBytes(t, plain.Payload)}},
+		{"deflate", http.Header{"Grpc-Encoding": {"deflate"}}, Frame{Flag: frameCompressed, Payload: zlibBytes(t, plain.Payload)}},
+		{"gzip sniffed", nil, Frame{Flag: frameCompressed, Payload: gzipBytes(t, plain.Payload)}},
+		{"deflate sniffed", nil, Frame{Flag: frameCompressed, Payload: zlibBytes(t, plain.Payload)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got pb.Pong
+			if err := decodeUnary(bytes.NewReader(body(tt.frame, okTrailer())), tt.header, &got); err != nil {
+				t.Fatal(err)
+			}
+			if !proto.Equal(&got, want) {
+				t.Fatalf("got %v, want %v", &got, want)
+			}
+		})
+	}
+}
+
+func TestDecodeUnaryErrors(t *testing.T) {
+	msg := messageFrame(t, &pb.Pong{C: 1})
+	t.Run("trailer status", func(t *testing.T) {
+		tr := Frame{Flag: frameTrailer, Payload: []byte("grpc-status:5\r\ngrpc-message:not%20found\r\n")}
+		err := decodeUnary(bytes.NewReader(body(msg, tr)), nil, &pb.Pong{})
+		var ge *GrpcError
+		if !errors.As(err, &ge) {
+			t.Fatalf("got %v, want *GrpcError", err)
+		}
+		if ge.Code() != 5 || ge.StatusMessage != "not found" {
+			t.Errorf("got code %d message %q", ge.Code(), ge.StatusMessage)
+		}
+	})
+	t.Run("trailers only", func(t *testing.T) {
+		h := http.Header{"Grpc-Status": {"14"}, "Grpc-Message": {"try%20later"}}
+		err := decodeUnary(bytes.NewReader(nil), h, &pb.Pong{})
+		var ge *GrpcError
+		if !errors.As(err, &ge) {
+			t.Fatalf("got %v, want *GrpcError", err)
+		}
+		if ge.Code() != 14 || ge.StatusMessage != "try later" {
+			t.Errorf("got code %d message %q", ge.Code(), ge.StatusMessage)
+		}
+	})
+	t.Run("no message", func(t *testing.T) {
+		err := decodeUnary(bytes.NewReader(body(okTrailer())), nil, &pb.Pong{})
+		if !errors.Is(err, ErrNoMessage) {
+			t.Fatalf("got %v, want ErrNoMessage", err)
+		}
+	})
+	t.Run("two messages", func(t *testing.T) {
+		err := decodeUnary(bytes.NewReader(body(msg, msg, okTrailer())), nil, &pb.Pong{})
+		if !errors.Is(err, ErrUnexpectedFrame) {
+			t.Fatalf("got %v, want ErrUnexpectedFrame", err)
+		}
+	})
+	t.Run("truncated", func(t *testing.T) {
+		raw := body(msg, okTrailer())
+		err := decodeUnary(bytes.NewReader(raw[:len(raw)-2]), nil, &pb.Pong{})
+		var ge *GrpcError
+		if !errors.As(err, &ge) {
+			t.Fatalf("got %v, want *GrpcError", err)
+		}
+	})
+	t.Run("bad compressed payload", func(t *testing.T) {
+		f := Frame{Flag: frameCompressed, Payload: []byte("junk")}
+		err := decodeUnary(bytes.NewReader(body(f, okTrailer())), http.Header{"Grpc-Encoding": {"gzip"}}, &pb.Pong{})
+		var ge *GrpcError
+		if !errors.As(err, &ge) {
+			t.Fatalf("got %v, want *GrpcError", err)
+		}
+	})
+}
+
+func TestParseTrailers(t *testing.T) {
+	ge := parseTrailers([]byte("Grpc-Status: 7\r\ngrpc-message: denied%21\r\ngrpc-status-details-bin: abc\r\n"))
+	if ge.Status != "7" || ge.StatusMessage != "denied!" || string(ge.StatusDetails) != "abc" {
+		t.Errorf("got %+v", ge)
+	}
+}
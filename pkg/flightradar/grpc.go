@@ -1,12 +1,15 @@
 package flightradar
 
 import (
-	"bufio"
 	"context"
+	"errors"
 	"io"
+	"iter"
 	"net/http"
+	"strings"
 
 	pb "github.com/igolaizola/fr24/pkg/proto"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
@@ -25,6 +28,21 @@ type LiveFeedParams struct {
 	Limit       int32
 	MaxAge      int32
 	Fields      []string
+	// Filter restricts results server-side (altitude, airline, type, ...).
+	Filter LiveFeedFilter
+	// Visibility settings: empty Sources/Services and a zero Traffic select
+	// everything.
+	Sources        []pb.DataSource
+	Services       []pb.Service
+	Traffic        pb.TrafficType
+	OnlyRestricted bool
+}
+
+func (p LiveFeedParams) validate() error {
+	if err := p.validateSettings(); err != nil {
+		return err
+	}
+	return p.Filter.Validate()
 }
 
 func (p LiveFeedParams) toProto() *pb.LiveFeedRequest {
@@ -40,12 +58,8 @@ func (p LiveFeedParams) toProto() *pb.LiveFeedRequest {
 			West:  p.BoundingBox.West,
 			East:  p.BoundingBox.East,
 		},
-		Settings: &pb.VisibilitySettings{
-			SourcesList:    []pb.DataSource{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
-			ServicesList:   []pb.Service{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
-			TrafficType:    pb.TrafficType_ALL,
-			OnlyRestricted: protoBool(false),
-		},
+		Settings:        p.settings(),
+		FiltersList:     p.Filter.toProto(),
 		FieldMask:       &fieldmaskpb.FieldMask{Paths: fields},
 		HighlightMode:   false,
 		Stats:           protoBool(p.Stats),
@@ -65,12 +79,10 @@ func protoInt32(v int32, def int32) *int32 {
 
 // GrpcLiveFeed sends the LiveFeed request and returns raw HTTP response.
 func (c *Client) GrpcLiveFeed(ctx context.Context, p LiveFeedParams) (*http.Response, error) {
-	reqHeaders := defaultGRPCHeaders(c.deviceID, c.grpcBearer())
-	req, err := constructGRPCRequest("LiveFeed", p.toProto(), reqHeaders)
-	if err != nil {
+	if err := p.validate(); err != nil {
 		return nil, err
 	}
-	return c.do(ctx, req)
+	return c.grpcCall(ctx, "LiveFeed", p.toProto())
 }
 
 // Playback (gRPC) request. Mirrors LiveFeedPlaybackParams in Python with
@@ -106,12 +118,10 @@ func max32(a, b int32) int32 {
 }
 
 func (c *Client) GrpcPlayback(ctx context.Context, p LiveFeedPlaybackParams) (*http.Response, error) {
-	reqHeaders := defaultGRPCHeaders(c.deviceID, c.grpcBearer())
-	req, err := constructGRPCRequest("Playback", p.toProto(), reqHeaders)
-	if err != nil {
+	if err := p.LiveFeed.validate(); err != nil {
 		return nil, err
 	}
-	return c.do(ctx, req)
+	return c.grpcCall(ctx, "Playback", p.toProto())
 }
 
 // Nearest flights
@@ -134,12 +144,7 @@ func (p NearestFlightsParams) toProto() *pb.NearestFlightsRequest {
 	}
 }
 func (c *Client) GrpcNearestFlights(ctx context.Context, p NearestFlightsParams) (*http.Response, error) {
-	reqHeaders := defaultGRPCHeaders(c.deviceID, c.grpcBearer())
-	req, err := constructGRPCRequest("NearestFlights", p.toProto(), reqHeaders)
-	if err != nil {
-		return nil, err
-	}
-	return c.do(ctx, req)
+	return c.grpcCall(ctx, "NearestFlights", p.toProto())
 }
 
 // Live flights status
@@ -149,12 +154,7 @@ func (p LiveFlightsStatusParams) toProto() *pb.LiveFlightsStatusRequest {
 	return &pb.LiveFlightsStatusRequest{FlightIdsList: p.FlightIDs}
 }
 func (c *Client) GrpcLiveFlightsStatus(ctx context.Context, p LiveFlightsStatusParams) (*http.Response, error) {
-	reqHeaders := defaultGRPCHeaders(c.deviceID, c.grpcBearer())
-	req, err := constructGRPCRequest("LiveFlightsStatus", p.toProto(), reqHeaders)
-	if err != nil {
-		return nil, err
-	}
-	return c.do(ctx, req)
+	return c.grpcCall(ctx, "LiveFlightsStatus", p.toProto())
 }
 
 // Top flights
@@ -167,32 +167,22 @@ func (p TopFlightsParams) toProto() *pb.TopFlightsRequest {
 	return &pb.TopFlightsRequest{Limit: uint32(p.Limit)}
 }
 func (c *Client) GrpcTopFlights(ctx context.Context, p TopFlightsParams) (*http.Response, error) {
-	reqHeaders := defaultGRPCHeaders(c.deviceID, c.grpcBearer())
-	req, err := constructGRPCRequest("TopFlights", p.toProto(), reqHeaders)
-	if err != nil {
-		return nil, err
-	}
-	return c.do(ctx, req)
+	return c.grpcCall(ctx, "TopFlights", p.toProto())
 }
 
 // Live trail
 func (c *Client) GrpcLiveTrail(ctx context.Context, flightID uint32) (*http.Response, error) {
-	reqHeaders := defaultGRPCHeaders(c.deviceID, c.grpcBearer())
-	req, err := constructGRPCRequest("LiveTrail", &pb.LiveTrailRequest{FlightId: flightID}, reqHeaders)
-	if err != nil {
-		return nil, err
-	}
-	return c.do(ctx, req)
+	return c.grpcCall(ctx, "LiveTrail", &pb.LiveTrailRequest{FlightId: flightID})
 }
 
 // Historic trail
 func (c *Client) GrpcHistoricTrail(ctx context.Context, flightID uint32) (*http.Response, error) {
-	reqHeaders := defaultGRPCHeaders(c.deviceID, c.grpcBearer())
-	req, err := constructGRPCRequest("HistoricTrail", &pb.HistoricTrailRequest{FlightId: flightID}, reqHeaders)
-	if err != nil {
-		return nil, err
-	}
-	return c.do(ctx, req)
+	return c.grpcCall(ctx, "HistoricTrail", &pb.HistoricTrailRequest{FlightId: flightID})
+}
+
+// Search index (every live flight, for lookups by callsign or registration)
+func (c *Client) GrpcFetchSearchIndex(ctx context.Context) (*http.Response, error) {
+	return c.grpcCall(ctx, "FetchSearchIndex", &pb.FetchSearchIndexRequest{})
 }
 
 // Flight details (live)
@@ -206,12 +196,7 @@ func (p FlightDetailsParams) toProto() *pb.FlightDetailsRequest {
 	return &pb.FlightDetailsRequest{FlightId: p.FlightID, RestrictionMode: p.Restriction, Verbose: p.Verbose}
 }
 func (c *Client) GrpcFlightDetails(ctx context.Context, p FlightDetailsParams) (*http.Response, error) {
-	reqHeaders := defaultGRPCHeaders(c.deviceID, c.grpcBearer())
-	req, err := constructGRPCRequest("FlightDetails", p.toProto(), reqHeaders)
-	if err != nil {
-		return nil, err
-	}
-	return c.do(ctx, req)
+	return c.grpcCall(ctx, "FlightDetails", p.toProto())
 }
 
 // Playback flight (historic)
@@ -224,57 +209,130 @@ func (p PlaybackFlightParams) toProto() *pb.PlaybackFlightRequest {
 	return &pb.PlaybackFlightRequest{FlightId: p.FlightID, Timestamp: p.Timestamp}
 }
 func (c *Client) GrpcPlaybackFlight(ctx context.Context, p PlaybackFlightParams) (*http.Response, error) {
-	reqHeaders := defaultGRPCHeaders(c.deviceID, c.grpcBearer())
-	req, err := constructGRPCRequest("PlaybackFlight", p.toProto(), reqHeaders)
-	if err != nil {
-		return nil, err
-	}
-	return c.do(ctx, req)
+	return c.grpcCall(ctx, "PlaybackFlight", p.toProto())
 }
 
 // Follow flight streaming: returns a channel of raw frames and a cancel function.
 func (c *Client) GrpcFollowFlightStream(ctx context.Context, flightID uint32, restriction pb.RestrictionVisibility) (<-chan []byte, func(), error) {
-	reqHeaders := defaultGRPCHeaders(c.deviceID, c.grpcBearer())
-	req, err := constructGRPCRequest("FollowFlight", &pb.FollowFlightRequest{FlightId: flightID, RestrictionMode: restriction}, reqHeaders)
-	if err != nil {
-		return nil, nil, err
-	}
-	// Force no overall timeout to keep stream open unless caller cancels.
-	hc := *c.http
-	hc.Timeout = 0
-	resp, err := hc.Do(req.WithContext(ctx))
+	resp, err := c.openFollowFlight(ctx, flightID, restriction)
 	if err != nil {
 		return nil, nil, err
 	}
 	ch := make(chan []byte, 8)
 	done := make(chan struct{})
-    go func() {
-        defer close(ch)
-        defer func() { _ = resp.Body.Close() }()
-		br := bufio.NewReader(resp.Body)
+	go func() {
+		defer close(ch)
+		defer func() { _ = resp.Body.Close() }()
+		fr := NewFrameReader(resp.Body)
 		for {
-			// Read until EOF; deliver raw frames (as chunks may align with frames).
-			// We read by frame prefix: 1 + 4 bytes, then payload.
-			header := make([]byte, 5)
-			if _, err := io.ReadFull(br, header); err != nil {
-				return
-			}
-			n := int(header[1])<<24 | int(header[2])<<16 | int(header[3])<<8 | int(header[4])
-			payload := make([]byte, n)
-			if _, err := io.ReadFull(br, payload); err != nil {
+			// Read until EOF; deliver message and trailer frames in order.
+			f, err := fr.Next()
+			if err != nil {
 				return
 			}
-			// Reassemble the full frame to match parseData expectations
-			frame := append(header[:5:5], payload...)
 			select {
-			case ch <- frame:
+			case ch <- f.Bytes():
 			case <-done:
 				return
 			}
 		}
 	}()
-    cancel := func() { close(done); _ = resp.Body.Close() }
-    return ch, cancel, nil
+	cancel := func() { close(done); _ = resp.Body.Close() }
+	return ch, cancel, nil
+}
+
+// grpcCall sends a unary gRPC-web request and returns the raw HTTP response.
+func (c *Client) grpcCall(ctx context.Context, method string, msg proto.Message) (*http.Response, error) {
+	req, err := constructGRPCRequest(c.endpoints.GRPC, method, msg, c.grpcHeaders())
+	if err != nil {
+		return nil, err
+	}
+	if kind, key := grpcCacheKey(method, msg); key != "" {
+		return c.doCached(ctx, req, kind, key, "application/grpc-web+proto", storeGRPC)
+	}
+	return c.do(ctx, req)
+}
+
+// openFollowFlight starts the FollowFlight server stream and returns the
+// response whose body carries the frames.
+func (c *Client) openFollowFlight(ctx context.Context, flightID uint32, restriction pb.RestrictionVisibility) (*http.Response, error) {
+	return c.openStream(ctx, "FollowFlight", &pb.FollowFlightRequest{FlightId: flightID, RestrictionMode: restriction})
+}
+
+// openStream starts a server stream and returns the response whose body
+// carries the frames.
+func (c *Client) openStream(ctx context.Context, method string, msg proto.Message) (*http.Response, error) {
+	req, err := constructGRPCRequest(c.endpoints.GRPC, method, msg, c.grpcHeaders())
+	if err != nil {
+		return nil, err
+	}
+	if err := c.wait(ctx, familyGRPC); err != nil {
+		return nil, err
+	}
+	// Force no overall timeout to keep stream open unless caller cancels.
+	hc := *c.http
+	hc.Timeout = 0
+	return hc.Do(req.WithContext(ctx))
+}
+
+// readFollowFlight decodes FollowFlight frames from an open stream, calling fn
+// for each message until fn returns false or the stream ends. It returns nil
+// when the stream ends cleanly (EOF or grpc-status 0) and a *GrpcError for a
+// trailers-only response or a non-zero trailer status.
+func readFollowFlight(resp *http.Response, fn func(*pb.FollowFlightResponse) bool) error {
+	return readStream(resp, func() *pb.FollowFlightResponse { return new(pb.FollowFlightResponse) }, fn)
+}
+
+// readStream decodes the frames of an open server stream into messages made
+// by newMsg, with the same semantics as readFollowFlight.
+func readStream[T proto.Message](resp *http.Response, newMsg func() T, fn func(T) bool) error {
+	if err := headerStatus(resp.Header); err != nil {
+		return err
+	}
+	encoding := resp.Header.Get("grpc-encoding")
+	fr := NewFrameReader(resp.Body)
+	for {
+		f, err := fr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if f.IsTrailer() {
+			return trailerStatus(f)
+		}
+		msg := newMsg()
+		if err := decodeMessage(f, encoding, msg); err != nil {
+			return err
+		}
+		if !fn(msg) {
+			return nil
+		}
+	}
+}
+
+// GrpcFollowFlight opens a FollowFlight stream and yields each frame decoded as
+// a FollowFlightResponse. A trailer frame with grpc-status 0 ends the sequence
+// cleanly; any other trailer or a decoding failure is yielded as an error and
+// ends the sequence. Breaking out of the loop closes the stream.
+func (c *Client) GrpcFollowFlight(ctx context.Context, flightID uint32, restriction pb.RestrictionVisibility) iter.Seq2[*pb.FollowFlightResponse, error] {
+	return func(yield func(*pb.FollowFlightResponse, error) bool) {
+		resp, err := c.openFollowFlight(ctx, flightID, restriction)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer func() { _ = resp.Body.Close() }()
+		stopped := false
+		err = readFollowFlight(resp, func(msg *pb.FollowFlightResponse) bool {
+			stopped = !yield(msg, nil)
+			return !stopped
+		})
+		if err != nil && !stopped {
+			yield(nil, err)
+		}
+	}
 }
 
 // Helpers to extract token for grpc headers.
@@ -286,3 +344,20 @@ func (c *Client) grpcBearer() string {
 
 // Optional: set bearer for grpc auth flows.
 func (c *Client) WithAuthToken(token string) *Client { c.authToken = token; return c }
+
+// WithGrpcAcceptEncoding advertises the given encodings (e.g. "gzip") via the
+// grpc-accept-encoding header so the server may compress large responses such
+// as LiveFeed and Playback. Each encoding must have a registered Codec.
+func (c *Client) WithGrpcAcceptEncoding(encodings ...string) *Client {
+	c.grpcAcceptEncoding = encodings
+	return c
+}
+
+// grpcHeaders returns the headers for a gRPC-web request from this client.
+func (c *Client) grpcHeaders() http.Header {
+	h := defaultGRPCHeaders(c.deviceID, c.grpcBearer())
+	if len(c.grpcAcceptEncoding) > 0 {
+		h.Set("grpc-accept-encoding", strings.Join(c.grpcAcceptEncoding, ","))
+	}
+	return h
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 
 	pb "github.com/igolaizola/fr24/pkg/proto"
@@ -26,42 +27,28 @@ func encodeMessage(m proto.Message) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-// parseData parses a single DATA frame payload into the target message.
-// It mirrors the Python parse_data behavior: erroring on compressed frames and
-// decoding trailers as gRPC errors.
+// parseData parses a complete gRPC-web response body (message and trailer
+// frames) into the target message. Compressed frames are inflated by sniffing
+// the payload; use ParseGRPCEncoded when the grpc-encoding header is known.
 func parseData(data []byte, into proto.Message) error {
-	if len(data) == 0 {
-		return &GrpcError{Message: "empty DATA frame", Raw: data}
-	}
-	flag := data[0]
-	if flag == 1 {
-		return &GrpcError{Message: "message is compressed, not implemented", Raw: data}
-	}
-	if flag != 0 {
-		// trailers frame
-		return parseTrailers(data)
-	}
-	if len(data) < 5 {
-		return &GrpcError{Message: "short frame", Raw: data}
-	}
-	n := binary.BigEndian.Uint32(data[1:5])
-	if n == 0 {
-		return &GrpcError{Message: "empty message payload", Raw: data}
-	}
-	msg := data[5 : 5+int(n)]
-	if err := proto.Unmarshal(msg, into); err != nil {
-		return &ProtoParseError{Err: fmt.Errorf("failed to parse message: %w", err), Raw: data}
-	}
-	return nil
+	return decodeUnary(bytes.NewReader(data), nil, into)
+}
+
+// ParseGRPCEncoded parses a gRPC-web response body into the target message,
+// decompressing frames with the codec registered for encoding (the
+// grpc-encoding response header).
+func ParseGRPCEncoded(data []byte, encoding string, into proto.Message) error {
+	return decodeUnary(bytes.NewReader(data), http.Header{"Grpc-Encoding": {encoding}}, into)
 }
 
-// constructGRPCRequest builds an HTTP request for the FR24 gRPC-web endpoint.
-func constructGRPCRequest(method string, message proto.Message, headers http.Header) (*http.Request, error) {
+// constructGRPCRequest builds an HTTP request for method on the gRPC-web
+// endpoint at baseURL.
+func constructGRPCRequest(baseURL, method string, message proto.Message, headers http.Header) (*http.Request, error) {
 	body, err := encodeMessage(message)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("POST", "https://data-feed.flightradar24.com/fr24.feed.api.v1.Feed/"+method, bytes.NewReader(body))
+	req, err := http.NewRequest("POST", baseURL+"/fr24.feed.api.v1.Feed/"+method, bytes.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
@@ -83,24 +70,6 @@ func constructGRPCRequest(method string, message proto.Message, headers http.Hea
 	return req, nil
 }
 
-// parseTrailers extracts grpc-status and grpc-message from a trailer frame.
-func parseTrailers(data []byte) error {
-	// Skip 5-byte header, remainder contains trailers as ASCII lines
-	trailers := data[5:]
-	lines := bytes.Split(bytes.TrimSpace(trailers), []byte{'\n'})
-	ge := &GrpcError{Message: "gRPC errored", Raw: data}
-	for _, ln := range lines {
-		if bytes.HasPrefix(ln, []byte("grpc-status:")) {
-			ge.Status = string(bytes.TrimSpace(ln[len("grpc-status:"):]))
-		} else if bytes.HasPrefix(ln, []byte("grpc-message:")) {
-			ge.StatusMessage = string(bytes.TrimSpace(ln[len("grpc-message:"):]))
-		} else if bytes.HasPrefix(ln, []byte("grpc-status-details-bin:")) {
-			ge.StatusDetails = ln[len("grpc-status-details-bin:"):]
-		}
-	}
-	return ge
-}
-
 // GrpcError mirrors Python's GrpcError with minimal fields.
 type GrpcError struct {
 	Message       string
@@ -132,72 +101,107 @@ func (e *ProtoParseError) Error() string {
 	return e.Err.Error()
 }
 
-// Helpers to decode into concrete response types.
-func parseLiveFeedResponse(data []byte) (*pb.LiveFeedResponse, error) {
+// Helpers to decode into concrete response types. header may be nil when
+// only the body is available.
+func parseLiveFeedResponse(r io.Reader, h http.Header) (*pb.LiveFeedResponse, error) {
 	var out pb.LiveFeedResponse
-	return &out, parseData(data, &out)
+	return &out, decodeUnary(r, h, &out)
 }
 
-func parsePlaybackResponse(data []byte) (*pb.PlaybackResponse, error) {
+func parsePlaybackResponse(r io.Reader, h http.Header) (*pb.PlaybackResponse, error) {
 	var out pb.PlaybackResponse
-	return &out, parseData(data, &out)
-}
-
-func parseNearestFlightsResponse(data []byte) (*pb.NearestFlightsResponse, error) {
-    var out pb.NearestFlightsResponse
-    if err := parseData(data, &out); err != nil {
-        if ge, ok := err.(*GrpcError); ok {
-            // Some deployments occasionally return a zero-length DATA frame
-            // for NearestFlights when there are no nearby results. Treat this
-            // as an empty response instead of an error to align with expected
-            // semantics (empty list of flights).
-            if ge.Message == "empty message payload" || ge.Message == "empty DATA frame" {
-                return &out, nil
-            }
-        }
-        return nil, err
-    }
-    return &out, nil
-}
-
-func parseLiveFlightsStatusResponse(data []byte) (*pb.LiveFlightsStatusResponse, error) {
+	return &out, decodeUnary(r, h, &out)
+}
+
+func parseNearestFlightsResponse(r io.Reader, h http.Header) (*pb.NearestFlightsResponse, error) {
+	var out pb.NearestFlightsResponse
+	if err := decodeUnary(r, h, &out); err != nil {
+		// Some deployments occasionally return no DATA frame for
+		// NearestFlights when there are no nearby results. Treat this as an
+		// empty response instead of an error to align with expected
+		// semantics (empty list of flights).
+		if errors.Is(err, ErrNoMessage) {
+			return &out, nil
+		}
+		return nil, err
+	}
+	return &out, nil
+}
+
+func parseLiveFlightsStatusResponse(r io.Reader, h http.Header) (*pb.LiveFlightsStatusResponse, error) {
 	var out pb.LiveFlightsStatusResponse
-	return &out, parseData(data, &out)
+	return &out, decodeUnary(r, h, &out)
 }
 
-func parseTopFlightsResponse(data []byte) (*pb.TopFlightsResponse, error) {
+func parseTopFlightsResponse(r io.Reader, h http.Header) (*pb.TopFlightsResponse, error) {
 	var out pb.TopFlightsResponse
-	return &out, parseData(data, &out)
+	return &out, decodeUnary(r, h, &out)
 }
 
-func parseFlightDetailsResponse(data []byte) (*pb.FlightDetailsResponse, error) {
+func parseFlightDetailsResponse(r io.Reader, h http.Header) (*pb.FlightDetailsResponse, error) {
 	var out pb.FlightDetailsResponse
-	return &out, parseData(data, &out)
+	return &out, decodeUnary(r, h, &out)
 }
 
-func parsePlaybackFlightResponse(data []byte) (*pb.PlaybackFlightResponse, error) {
+func parsePlaybackFlightResponse(r io.Reader, h http.Header) (*pb.PlaybackFlightResponse, error) {
 	var out pb.PlaybackFlightResponse
-	return &out, parseData(data, &out)
+	return &out, decodeUnary(r, h, &out)
+}
+
+func parseFollowFlightResponse(r io.Reader, h http.Header) (*pb.FollowFlightResponse, error) {
+	var out pb.FollowFlightResponse
+	return &out, decodeUnary(r, h, &out)
+}
+
+func parseLiveTrailResponse(r io.Reader, h http.Header) (*pb.LiveTrailResponse, error) {
+	var out pb.LiveTrailResponse
+	return &out, decodeUnary(r, h, &out)
+}
+
+func parseHistoricTrailResponse(r io.Reader, h http.Header) (*pb.HistoricTrailResponse, error) {
+	var out pb.HistoricTrailResponse
+	return &out, decodeUnary(r, h, &out)
+}
+
+func parseFetchSearchIndexResponse(r io.Reader, h http.Header) (*pb.FetchSearchIndexResponse, error) {
+	var out pb.FetchSearchIndexResponse
+	return &out, decodeUnary(r, h, &out)
 }
 
 // util
 var ErrUnexpectedFrame = errors.New("unexpected gRPC-web frame")
 
-// Exported parse helpers for consumers.
-func ParseLiveFeedGRPC(data []byte) (*pb.LiveFeedResponse, error) { return parseLiveFeedResponse(data) }
-func ParsePlaybackGRPC(data []byte) (*pb.PlaybackResponse, error) { return parsePlaybackResponse(data) }
+// Exported parse helpers for consumers. They accept a full response body.
+func ParseLiveFeedGRPC(data []byte) (*pb.LiveFeedResponse, error) {
+	return parseLiveFeedResponse(bytes.NewReader(data), nil)
+}
+func ParsePlaybackGRPC(data []byte) (*pb.PlaybackResponse, error) {
+	return parsePlaybackResponse(bytes.NewReader(data), nil)
+}
 func ParseNearestFlightsGRPC(data []byte) (*pb.NearestFlightsResponse, error) {
-	return parseNearestFlightsResponse(data)
+	return parseNearestFlightsResponse(bytes.NewReader(data), nil)
 }
 func ParseLiveFlightsStatusGRPC(data []byte) (*pb.LiveFlightsStatusResponse, error) {
-	return parseLiveFlightsStatusResponse(data)
+	return parseLiveFlightsStatusResponse(bytes.NewReader(data), nil)
 }
 func ParseTopFlightsGRPC(data []byte) (*pb.TopFlightsResponse, error) {
-	return parseTopFlightsResponse(data)
+	return parseTopFlightsResponse(bytes.NewReader(data), nil)
 }
 func ParseFlightDetailsGRPC(data []byte) (*pb.FlightDetailsResponse, error) {
-	return parseFlightDetailsResponse(data)
+	return parseFlightDetailsResponse(bytes.NewReader(data), nil)
 }
 func ParsePlaybackFlightGRPC(data []byte) (*pb.PlaybackFlightResponse, error) {
-	return parsePlaybackFlightResponse(data)
+	return parsePlaybackFlightResponse(bytes.NewReader(data), nil)
+}
+func ParseFollowFlightGRPC(data []byte) (*pb.FollowFlightResponse, error) {
+	return parseFollowFlightResponse(bytes.NewReader(data), nil)
+}
+func ParseLiveTrailGRPC(data []byte) (*pb.LiveTrailResponse, error) {
+	return parseLiveTrailResponse(bytes.NewReader(data), nil)
+}
+func ParseHistoricTrailGRPC(data []byte) (*pb.HistoricTrailResponse, error) {
+	return parseHistoricTrailResponse(bytes.NewReader(data), nil)
+}
+func ParseFetchSearchIndexGRPC(data []byte) (*pb.FetchSearchIndexResponse, error) {
+	return parseFetchSearchIndexResponse(bytes.NewReader(data), nil)
 }
@@ -0,0 +1,78 @@
+package flightradar
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"time"
+
+	pb "github.com/igolaizola/fr24/pkg/proto"
+)
+
+// PingResult is the outcome of an Echo round trip.
+type PingResult struct {
+	// Latency is the time from sending the request to decoding the reply of
+	// a single attempt.
+	Latency time.Duration `json:"latency"`
+	// AuthMode is the client's auth configuration, see Client.AuthMode.
+	AuthMode string `json:"auth_mode"`
+	// Pong is the value returned by the server.
+	Pong uint32 `json:"pong"`
+}
+
+// Ping calls the Echo RPC and reports the round-trip latency. A successful
+// ping means the gRPC-web endpoint is reachable and accepted the client's
+// credentials. Ping makes exactly one attempt and bypasses the rate limits,
+// so neither queueing nor retries hide a slow or flaky endpoint; the error
+// is that of the single attempt.
+func (c *Client) Ping(ctx context.Context) (PingResult, error) {
+	res := PingResult{AuthMode: c.AuthMode()}
+	req, err := constructGRPCRequest(c.endpoints.GRPC, "Echo", &pb.Ping{A: 1, B: 2}, c.grpcHeaders())
+	if err != nil {
+		return res, err
+	}
+	req = c.prepare(ctx, req)
+	pong := new(pb.Pong)
+	start := time.Now()
+	resp, err := c.http.Do(req)
+	if err == nil {
+		err = readUnary(resp, pong)
+	}
+	res.Latency = time.Since(start)
+	if err != nil {
+		return res, err
+	}
+	res.Pong = pong.GetC()
+	return res, nil
+}
+
+// CountDown opens the CountDown server stream and yields each tick as it is
+// received. The server counts down from count; a clean end of stream ends
+// the sequence, and an HTTP, trailer or decoding error is yielded and ends
+// it. Breaking out of the loop closes the stream.
+func (c *Client) CountDown(ctx context.Context, count uint32) iter.Seq2[*pb.Tick, error] {
+	return func(yield func(*pb.Tick, error) bool) {
+		resp, err := c.openStream(ctx, "CountDown", &pb.Duration{Count: count})
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer func() { _ = resp.Body.Close() }()
+		if resp.StatusCode != http.StatusOK {
+			if err := headerStatus(resp.Header); err != nil {
+				yield(nil, err)
+				return
+			}
+			yield(nil, newHTTPError(resp))
+			return
+		}
+		stopped := false
+		err = readStream(resp, func() *pb.Tick { return new(pb.Tick) }, func(t *pb.Tick) bool {
+			stopped = !yield(t, nil)
+			return !stopped
+		})
+		if err != nil && !stopped {
+			yield(nil, err)
+		}
+	}
+}
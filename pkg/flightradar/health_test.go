@@ -0,0 +1,39 @@
+package flightradar
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPingSingleAttempt(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	// A retry policy and an exhausted rate limit must not affect Ping.
+	c := New().WithEndpoints(Endpoints{GRPC: srv.URL}).
+		WithRetry(RetryPolicy{MaxAttempts: 4, BaseDelay: time.Second, MaxDelay: time.Second}).
+		WithRateLimits(RateLimits{GRPC: RateLimit{RPS: 0.001, Burst: 1}})
+	_ = c.wait(context.Background(), familyGRPC)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	res, err := c.Ping(ctx)
+	var he *HTTPError
+	if !errors.As(err, &he) || he.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Ping() error = %v, want HTTP 503", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("got %d requests, want 1", got)
+	}
+	if res.Latency <= 0 || res.Latency > time.Second {
+		t.Errorf("latency %s, want the single round trip", res.Latency)
+	}
+}
@@ -0,0 +1,124 @@
+package flightradar
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	pb "github.com/igolaizola/fr24/pkg/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// Invoke performs a unary gRPC-web call and decodes the response. It checks
+// the HTTP status, reads every frame, honours trailers (including
+// trailers-only responses) and returns a *GrpcError for non-zero statuses.
+func Invoke[Req, Resp proto.Message](ctx context.Context, c *Client, method string, req Req) (Resp, error) {
+	var zero Resp
+	resp, err := c.grpcCall(ctx, method, req)
+	if err != nil {
+		return zero, err
+	}
+	out := zero.ProtoReflect().New().Interface().(Resp)
+	if err := readUnary(resp, out); err != nil {
+		return zero, err
+	}
+	return out, nil
+}
+
+// readUnary checks the HTTP status of a unary response, decodes its message
+// into out and closes the body.
+func readUnary(resp *http.Response, out proto.Message) error {
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		if err := headerStatus(resp.Header); err != nil {
+			return err
+		}
+		return newHTTPError(resp)
+	}
+	return decodeUnary(resp.Body, resp.Header, out)
+}
+
+// HTTPError is returned when the server answers with an unexpected HTTP status.
+type HTTPError struct {
+	StatusCode int
+	Status     string
+	Header     http.Header
+	Body       []byte
+}
+
+func (e *HTTPError) Error() string {
+	if e == nil {
+		return ""
+	}
+	return "unexpected status " + e.Status
+}
+
+// newHTTPError captures the status and a bounded prefix of the body.
+func newHTTPError(resp *http.Response) *HTTPError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return &HTTPError{StatusCode: resp.StatusCode, Status: resp.Status, Header: resp.Header, Body: body}
+}
+
+// LiveFeed returns the decoded LiveFeed response.
+func (c *Client) LiveFeed(ctx context.Context, p LiveFeedParams) (*pb.LiveFeedResponse, error) {
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return Invoke[*pb.LiveFeedRequest, *pb.LiveFeedResponse](ctx, c, "LiveFeed", p.toProto())
+}
+
+// PlaybackFeed returns the decoded historic live feed (Playback RPC). It is
+// not named Playback because that is the JSON flight playback call.
+func (c *Client) PlaybackFeed(ctx context.Context, p LiveFeedPlaybackParams) (*pb.PlaybackResponse, error) {
+	if err := p.LiveFeed.validate(); err != nil {
+		return nil, err
+	}
+	return Invoke[*pb.PlaybackRequest, *pb.PlaybackResponse](ctx, c, "Playback", p.toProto())
+}
+
+// NearestFlights returns the decoded NearestFlights response. A response
+// without a message frame is treated as no nearby flights.
+func (c *Client) NearestFlights(ctx context.Context, p NearestFlightsParams) (*pb.NearestFlightsResponse, error) {
+	out, err := Invoke[*pb.NearestFlightsRequest, *pb.NearestFlightsResponse](ctx, c, "NearestFlights", p.toProto())
+	if errors.Is(err, ErrNoMessage) {
+		return &pb.NearestFlightsResponse{}, nil
+	}
+	return out, err
+}
+
+// LiveFlightsStatus returns the decoded LiveFlightsStatus response.
+func (c *Client) LiveFlightsStatus(ctx context.Context, p LiveFlightsStatusParams) (*pb.LiveFlightsStatusResponse, error) {
+	return Invoke[*pb.LiveFlightsStatusRequest, *pb.LiveFlightsStatusResponse](ctx, c, "LiveFlightsStatus", p.toProto())
+}
+
+// TopFlights returns the decoded TopFlights response.
+func (c *Client) TopFlights(ctx context.Context, p TopFlightsParams) (*pb.TopFlightsResponse, error) {
+	return Invoke[*pb.TopFlightsRequest, *pb.TopFlightsResponse](ctx, c, "TopFlights", p.toProto())
+}
+
+// LiveTrail returns the decoded LiveTrail response.
+func (c *Client) LiveTrail(ctx context.Context, flightID uint32) (*pb.LiveTrailResponse, error) {
+	return Invoke[*pb.LiveTrailRequest, *pb.LiveTrailResponse](ctx, c, "LiveTrail", &pb.LiveTrailRequest{FlightId: flightID})
+}
+
+// HistoricTrail returns the decoded HistoricTrail response.
+func (c *Client) HistoricTrail(ctx context.Context, flightID uint32) (*pb.HistoricTrailResponse, error) {
+	return Invoke[*pb.HistoricTrailRequest, *pb.HistoricTrailResponse](ctx, c, "HistoricTrail", &pb.HistoricTrailRequest{FlightId: flightID})
+}
+
+// FetchSearchIndex returns the decoded FetchSearchIndex response: a compact
+// entry for every live flight.
+func (c *Client) FetchSearchIndex(ctx context.Context) (*pb.FetchSearchIndexResponse, error) {
+	return Invoke[*pb.FetchSearchIndexRequest, *pb.FetchSearchIndexResponse](ctx, c, "FetchSearchIndex", &pb.FetchSearchIndexRequest{})
+}
+
+// FlightDetails returns the decoded FlightDetails response.
+func (c *Client) FlightDetails(ctx context.Context, p FlightDetailsParams) (*pb.FlightDetailsResponse, error) {
+	return Invoke[*pb.FlightDetailsRequest, *pb.FlightDetailsResponse](ctx, c, "FlightDetails", p.toProto())
+}
+
+// PlaybackFlight returns the decoded PlaybackFlight response.
+func (c *Client) PlaybackFlight(ctx context.Context, p PlaybackFlightParams) (*pb.PlaybackFlightResponse, error) {
+	return Invoke[*pb.PlaybackFlightRequest, *pb.PlaybackFlightResponse](ctx, c, "PlaybackFlight", p.toProto())
+}
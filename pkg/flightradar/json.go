@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 )
 
 // ---- Flight List ----
@@ -75,8 +76,12 @@ func (c *Client) FlightList(ctx context.Context, p FlightListParams) (*http.Resp
 	}
 	withAuthParams(&q, c.subscriptionKey, c.deviceID)
 
-	req, _ := http.NewRequest("GET", "https://api.flightradar24.com/common/v1/flight/list.json", nil)
+	req, _ := http.NewRequest("GET", c.endpoints.API+"/common/v1/flight/list.json", nil)
 	req.URL.RawQuery = q.Encode()
+	if p.TimestampS != nil && historic(*p.TimestampS) {
+		key := fmt.Sprintf("%s_%s_%d_%d_%d.json", q.Get("fetchBy"), cacheKeyPart(q.Get("query")), zeroDefault(p.Page, 1), zeroDefault(p.Limit, 10), *p.TimestampS)
+		return c.doCached(ctx, req, CacheFlightList, key, "application/json", storeJSONResult)
+	}
 	return c.do(ctx, req)
 }
 
@@ -212,11 +217,188 @@ func (c *Client) AirportList(ctx context.Context, p AirportListParams) (*http.Re
 	}
 	withAuthParams(&q, c.subscriptionKey, c.deviceID)
 
-	req, _ := http.NewRequest("GET", "https://api.flightradar24.com/common/v1/airport.json", nil)
+	req, _ := http.NewRequest("GET", c.endpoints.API+"/common/v1/airport.json", nil)
 	req.URL.RawQuery = q.Encode()
 	return c.do(ctx, req)
 }
 
+// AirportScheduleRecord is one flattened airport schedule entry.
+type AirportScheduleRecord struct {
+	Mode         string  `csv:"mode" json:"mode"`
+	FlightID     *int64  `csv:"flight_id" json:"flight_id,omitempty"`
+	Number       *string `csv:"number" json:"number,omitempty"`
+	Callsign     *string `csv:"callsign" json:"callsign,omitempty"`
+	AirlineICAO  *string `csv:"airline_icao" json:"airline_icao,omitempty"`
+	AirlineIATA  *string `csv:"airline_iata" json:"airline_iata,omitempty"`
+	AirlineName  *string `csv:"airline_name" json:"airline_name,omitempty"`
+	ICAO24       *int64  `csv:"icao24" json:"icao24,omitempty"`
+	Registration *string `csv:"registration" json:"registration,omitempty"`
+	Typecode     *string `csv:"typecode" json:"typecode,omitempty"`
+	Origin       *string `csv:"origin" json:"origin,omitempty"`
+	Destination  *string `csv:"destination" json:"destination,omitempty"`
+	Status       *string `csv:"status" json:"status,omitempty"`
+	Live         *bool   `csv:"live" json:"live,omitempty"`
+	STOD         *int64  `csv:"STOD" json:"STOD,omitempty"`
+	ETOD         *int64  `csv:"ETOD" json:"ETOD,omitempty"`
+	ATOD         *int64  `csv:"ATOD" json:"ATOD,omitempty"`
+	STOA         *int64  `csv:"STOA" json:"STOA,omitempty"`
+	ETOA         *int64  `csv:"ETOA" json:"ETOA,omitempty"`
+	ATOA         *int64  `csv:"ATOA" json:"ATOA,omitempty"`
+	// ground mode only
+	Latitude      *float64 `csv:"latitude" json:"latitude,omitempty"`
+	Longitude     *float64 `csv:"longitude" json:"longitude,omitempty"`
+	OnGroundSince *int64   `csv:"on_ground_since" json:"on_ground_since,omitempty"`
+}
+
+// ParseAirportList flattens the schedule entries of an airport list response
+// (arrivals, then departures, then ground). Times are in milliseconds; an
+// origin or destination left out because it is the queried airport is
+// filled in with that airport's ICAO code.
+func ParseAirportList(body []byte) ([]AirportScheduleRecord, error) {
+	type code struct {
+		IATA *string `json:"iata"`
+		ICAO *string `json:"icao"`
+	}
+	type times struct {
+		Departure *int64 `json:"departure"`
+		Arrival   *int64 `json:"arrival"`
+	}
+	type entry struct {
+		Flight struct {
+			Identification struct {
+				ID     *string `json:"id"`
+				Number struct {
+					Default *string `json:"default"`
+				} `json:"number"`
+				Callsign *string `json:"callsign"`
+			} `json:"identification"`
+			Status struct {
+				Live *bool   `json:"live"`
+				Text *string `json:"text"`
+			} `json:"status"`
+			Aircraft *struct {
+				Hex          *string `json:"hex"`
+				Registration *string `json:"registration"`
+				Model        struct {
+					Code *string `json:"code"`
+				} `json:"model"`
+				OnGroundUpdate *int64 `json:"onGroundUpdate"`
+			} `json:"aircraft"`
+			Airline *struct {
+				Name *string `json:"name"`
+				Code code    `json:"code"`
+			} `json:"airline"`
+			Airport struct {
+				Origin *struct {
+					Code code `json:"code"`
+				} `json:"origin"`
+				Destination *struct {
+					Code code `json:"code"`
+				} `json:"destination"`
+			} `json:"airport"`
+			Time struct {
+				Scheduled times `json:"scheduled"`
+				Estimated times `json:"estimated"`
+				Real      times `json:"real"`
+			} `json:"time"`
+			Position *struct {
+				Latitude  *float64 `json:"latitude"`
+				Longitude *float64 `json:"longitude"`
+			} `json:"position"`
+		} `json:"flight"`
+	}
+	type block struct {
+		Data []entry `json:"data"`
+	}
+	var root struct {
+		Result struct {
+			Response struct {
+				Airport struct {
+					PluginData struct {
+						Details struct {
+							Code code `json:"code"`
+						} `json:"details"`
+						Schedule struct {
+							Arrivals   *block `json:"arrivals"`
+							Departures *block `json:"departures"`
+							Ground     *block `json:"ground"`
+						} `json:"schedule"`
+					} `json:"pluginData"`
+				} `json:"airport"`
+			} `json:"response"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(body, &root); err != nil {
+		return nil, err
+	}
+	pd := root.Result.Response.Airport.PluginData
+	here := pd.Details.Code.ICAO
+	var out []AirportScheduleRecord
+	for _, b := range []struct {
+		mode  AirportMode
+		block *block
+	}{
+		{AirportArrivals, pd.Schedule.Arrivals},
+		{AirportDepartures, pd.Schedule.Departures},
+		{AirportGround, pd.Schedule.Ground},
+	} {
+		if b.block == nil {
+			continue
+		}
+		for _, e := range b.block.Data {
+			f := e.Flight
+			rec := AirportScheduleRecord{Mode: string(b.mode)}
+			// flight id (hex -> int)
+			if f.Identification.ID != nil {
+				if n, err := strconv.ParseInt(*f.Identification.ID, 16, 64); err == nil {
+					rec.FlightID = &n
+				}
+			}
+			rec.Number = f.Identification.Number.Default
+			rec.Callsign = f.Identification.Callsign
+			if f.Airline != nil {
+				rec.AirlineICAO, rec.AirlineIATA, rec.AirlineName = f.Airline.Code.ICAO, f.Airline.Code.IATA, f.Airline.Name
+			}
+			if a := f.Aircraft; a != nil {
+				if a.Hex != nil {
+					if n, err := strconv.ParseInt(*a.Hex, 16, 64); err == nil {
+						rec.ICAO24 = &n
+					}
+				}
+				rec.Registration = a.Registration
+				rec.Typecode = a.Model.Code
+				rec.OnGroundSince = mul1000(a.OnGroundUpdate)
+			}
+			if f.Airport.Origin != nil {
+				rec.Origin = f.Airport.Origin.Code.ICAO
+			}
+			if f.Airport.Destination != nil {
+				rec.Destination = f.Airport.Destination.Code.ICAO
+			}
+			switch {
+			case b.mode == AirportArrivals && rec.Destination == nil:
+				rec.Destination = here
+			case b.mode == AirportDepartures && rec.Origin == nil:
+				rec.Origin = here
+			}
+			rec.Status = f.Status.Text
+			rec.Live = f.Status.Live
+			// seconds -> ms
+			rec.STOD = mul1000(f.Time.Scheduled.Departure)
+			rec.ETOD = mul1000(f.Time.Estimated.Departure)
+			rec.ATOD = mul1000(f.Time.Real.Departure)
+			rec.STOA = mul1000(f.Time.Scheduled.Arrival)
+			rec.ETOA = mul1000(f.Time.Estimated.Arrival)
+			rec.ATOA = mul1000(f.Time.Real.Arrival)
+			if f.Position != nil {
+				rec.Latitude, rec.Longitude = f.Position.Latitude, f.Position.Longitude
+			}
+			out = append(out, rec)
+		}
+	}
+	return out, nil
+}
+
 // ---- Playback ----
 
 type PlaybackParams struct {
@@ -238,8 +420,12 @@ func (c *Client) Playback(ctx context.Context, p PlaybackParams) (*http.Response
 	}
 	withAuthParams(&q, c.subscriptionKey, c.deviceID)
 
-	req, _ := http.NewRequest("GET", "https://api.flightradar24.com/common/v1/flight-playback.json", nil)
+	req, _ := http.NewRequest("GET", c.endpoints.API+"/common/v1/flight-playback.json", nil)
 	req.URL.RawQuery = q.Encode()
+	if p.TimestampS != nil && historic(*p.TimestampS) {
+		key := fmt.Sprintf("%s_%d.json", cacheKeyPart(q.Get("flightId")), *p.TimestampS)
+		return c.doCached(ctx, req, CachePlayback, key, "application/json", storeJSONResult)
+	}
 	return c.do(ctx, req)
 }
 
@@ -377,6 +563,212 @@ func ParsePlayback(body []byte) ([]PlaybackTrack, error) {
 	return out, nil
 }
 
+// PlaybackAirport is an origin or destination airport of a playback flight.
+type PlaybackAirport struct {
+	Name      *string  `csv:"name" json:"name,omitempty"`
+	IATA      *string  `csv:"iata" json:"iata,omitempty"`
+	ICAO      *string  `csv:"icao" json:"icao,omitempty"`
+	Latitude  *float64 `csv:"latitude" json:"latitude,omitempty"`
+	Longitude *float64 `csv:"longitude" json:"longitude,omitempty"`
+	Altitude  *int64   `csv:"altitude" json:"altitude,omitempty"`
+	City      *string  `csv:"city" json:"city,omitempty"`
+	Country   *string  `csv:"country" json:"country,omitempty"`
+	// Timezone is the IANA name, e.g. "Asia/Hong_Kong"; TimezoneOffset is
+	// the UTC offset in seconds at the time of the flight.
+	Timezone       *string `csv:"timezone" json:"timezone,omitempty"`
+	TimezoneAbbr   *string `csv:"timezone_abbr" json:"timezone_abbr,omitempty"`
+	TimezoneOffset *int64  `csv:"timezone_offset" json:"timezone_offset,omitempty"`
+	DST            *bool   `csv:"dst" json:"dst,omitempty"`
+}
+
+// PlaybackFlight is the flight metadata of a playback response alongside its
+// track. Times are in milliseconds like the other flattened records.
+type PlaybackFlight struct {
+	FlightID     *int64  `csv:"flight_id" json:"flight_id,omitempty"`
+	Number       *string `csv:"number" json:"number,omitempty"`
+	Callsign     *string `csv:"callsign" json:"callsign,omitempty"`
+	ICAO24       *int64  `csv:"icao24" json:"icao24,omitempty"`
+	Registration *string `csv:"registration" json:"registration,omitempty"`
+	Typecode     *string `csv:"typecode" json:"typecode,omitempty"`
+	Model        *string `csv:"model" json:"model,omitempty"`
+	SerialNo     *string `csv:"serial_no" json:"serial_no,omitempty"`
+	// Age is the aircraft age as reported, usually its first flight or
+	// delivery date.
+	Age         *string          `csv:"age" json:"age,omitempty"`
+	AirlineName *string          `csv:"airline_name" json:"airline_name,omitempty"`
+	AirlineIATA *string          `csv:"airline_iata" json:"airline_iata,omitempty"`
+	AirlineICAO *string          `csv:"airline_icao" json:"airline_icao,omitempty"`
+	Origin      *PlaybackAirport `csv:"origin" json:"origin,omitempty"`
+	Destination *PlaybackAirport `csv:"destination" json:"destination,omitempty"`
+	STOD        *int64           `csv:"STOD" json:"STOD,omitempty"`
+	STOA        *int64           `csv:"STOA" json:"STOA,omitempty"`
+	ATOD        *int64           `csv:"ATOD" json:"ATOD,omitempty"`
+	ATOA        *int64           `csv:"ATOA" json:"ATOA,omitempty"`
+	Track       []PlaybackTrack  `csv:"-" json:"track,omitempty"`
+}
+
+// ParsePlaybackFlight decodes the flight metadata and track of a playback
+// response.
+func ParsePlaybackFlight(body []byte) (*PlaybackFlight, error) {
+	type code struct {
+		IATA *string `json:"iata"`
+		ICAO *string `json:"icao"`
+	}
+	type airport struct {
+		Name     *string `json:"name"`
+		Code     code    `json:"code"`
+		Position struct {
+			Latitude  *float64 `json:"latitude"`
+			Longitude *float64 `json:"longitude"`
+			Altitude  *int64   `json:"altitude"`
+			Country   struct {
+				Name *string `json:"name"`
+			} `json:"country"`
+			Region struct {
+				City *string `json:"city"`
+			} `json:"region"`
+		} `json:"position"`
+		Timezone struct {
+			Name   *string `json:"name"`
+			Offset *int64  `json:"offset"`
+			Abbr   *string `json:"abbr"`
+			IsDST  *bool   `json:"isDst"`
+		} `json:"timezone"`
+	}
+	type times struct {
+		Departure *int64 `json:"departure"`
+		Arrival   *int64 `json:"arrival"`
+	}
+	var root struct {
+		Result struct {
+			Response struct {
+				Data struct {
+					Flight struct {
+						Identification struct {
+							ID     *string `json:"id"`
+							Number struct {
+								Default *string `json:"default"`
+							} `json:"number"`
+							Callsign *string `json:"callsign"`
+						} `json:"identification"`
+						Aircraft struct {
+							Model struct {
+								Code *string `json:"code"`
+								Text *string `json:"text"`
+							} `json:"model"`
+							// Older responses carry hex and registration at
+							// this level, newer ones under identification.
+							Hex            *string `json:"hex"`
+							Registration   *string `json:"registration"`
+							Identification struct {
+								ModeS        *string         `json:"modes"`
+								Registration *string         `json:"registration"`
+								SerialNo     *string         `json:"serialNo"`
+								Age          json.RawMessage `json:"age"`
+							} `json:"identification"`
+							Age json.RawMessage `json:"age"`
+						} `json:"aircraft"`
+						Airline *struct {
+							Name *string `json:"name"`
+							Code code    `json:"code"`
+						} `json:"airline"`
+						Airport struct {
+							Origin      *airport `json:"origin"`
+							Destination *airport `json:"destination"`
+						} `json:"airport"`
+						Time struct {
+							Scheduled times `json:"scheduled"`
+							Real      times `json:"real"`
+						} `json:"time"`
+					} `json:"flight"`
+				} `json:"data"`
+			} `json:"response"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(body, &root); err != nil {
+		return nil, err
+	}
+	track, err := ParsePlayback(body)
+	if err != nil {
+		return nil, err
+	}
+	f := root.Result.Response.Data.Flight
+	out := &PlaybackFlight{Track: track}
+	if f.Identification.ID != nil {
+		if n, err := strconv.ParseInt(*f.Identification.ID, 16, 64); err == nil {
+			out.FlightID = &n
+		}
+	}
+	out.Number = f.Identification.Number.Default
+	out.Callsign = f.Identification.Callsign
+	a := f.Aircraft
+	if hex := firstNonNil(a.Hex, a.Identification.ModeS); hex != nil {
+		if n, err := strconv.ParseInt(*hex, 16, 64); err == nil {
+			out.ICAO24 = &n
+		}
+	}
+	out.Registration = firstNonNil(a.Registration, a.Identification.Registration)
+	out.Typecode, out.Model = a.Model.Code, a.Model.Text
+	out.SerialNo = a.Identification.SerialNo
+	out.Age = aircraftAge(a.Age)
+	if out.Age == nil {
+		out.Age = aircraftAge(a.Identification.Age)
+	}
+	if f.Airline != nil {
+		out.AirlineName, out.AirlineIATA, out.AirlineICAO = f.Airline.Name, f.Airline.Code.IATA, f.Airline.Code.ICAO
+	}
+	toAirport := func(ap *airport) *PlaybackAirport {
+		if ap == nil {
+			return nil
+		}
+		return &PlaybackAirport{
+			Name: ap.Name, IATA: ap.Code.IATA, ICAO: ap.Code.ICAO,
+			Latitude: ap.Position.Latitude, Longitude: ap.Position.Longitude, Altitude: ap.Position.Altitude,
+			City: ap.Position.Region.City, Country: ap.Position.Country.Name,
+			Timezone: ap.Timezone.Name, TimezoneAbbr: ap.Timezone.Abbr, TimezoneOffset: ap.Timezone.Offset, DST: ap.Timezone.IsDST,
+		}
+	}
+	out.Origin = toAirport(f.Airport.Origin)
+	out.Destination = toAirport(f.Airport.Destination)
+	// seconds -> ms
+	out.STOD = mul1000(f.Time.Scheduled.Departure)
+	out.STOA = mul1000(f.Time.Scheduled.Arrival)
+	out.ATOD = mul1000(f.Time.Real.Departure)
+	out.ATOA = mul1000(f.Time.Real.Arrival)
+	return out, nil
+}
+
+// aircraftAge reads an age value that is either a string, a number or an
+// object such as {"availability":true,"date":"2013-03-28"}.
+func aircraftAge(raw json.RawMessage) *string {
+	if len(raw) == 0 {
+		return nil
+	}
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil
+	}
+	switch x := v.(type) {
+	case string:
+		if x != "" {
+			return &x
+		}
+	case float64:
+		s := strconv.FormatFloat(x, 'f', -1, 64)
+		return &s
+	case map[string]any:
+		return detailString(x, "date")
+	}
+	return nil
+}
+
+func firstNonNil[T any](a, b *T) *T {
+	if a != nil {
+		return a
+	}
+	return b
+}
+
 // ---- Find ----
 
 type FindParams struct {
@@ -395,11 +787,215 @@ func (c *Client) Find(ctx context.Context, p FindParams) (*http.Response, error)
 	q.Set("query", p.Query)
 	q.Set("limit", strconv.Itoa(p.Limit))
 	withAuthParams(&q, c.subscriptionKey, c.deviceID)
-	req, _ := http.NewRequest("GET", "https://www.flightradar24.com/v1/search/web/find", nil)
+	req, _ := http.NewRequest("GET", c.endpoints.Web+"/v1/search/web/find", nil)
 	req.URL.RawQuery = q.Encode()
 	return c.do(ctx, req)
 }
 
+// Search result kinds.
+const (
+	SearchAirport  = "airport"
+	SearchOperator = "operator"
+	SearchAircraft = "aircraft"
+	SearchLive     = "live"
+	SearchSchedule = "schedule"
+)
+
+// SearchResults groups the results of a Find call by kind. Results of kinds
+// not listed here are kept in Other.
+type SearchResults struct {
+	Airports  []SearchAirportRecord  `json:"airports"`
+	Operators []SearchOperatorRecord `json:"operators"`
+	Aircraft  []SearchAircraftRecord `json:"aircraft"`
+	Live      []SearchLiveRecord     `json:"live"`
+	Schedule  []SearchScheduleRecord `json:"schedule"`
+	Other     []SearchEntry          `json:"other"`
+	// All lists every result in response order.
+	All []SearchEntry `json:"-"`
+}
+
+// SearchEntry is the part common to every search result.
+type SearchEntry struct {
+	Kind  string `csv:"kind" json:"kind"`
+	ID    string `csv:"id" json:"id"`
+	Label string `csv:"label" json:"label"`
+	Match string `csv:"match" json:"match"`
+}
+
+type SearchAirportRecord struct {
+	Name      string   `csv:"name" json:"name"`
+	IATA      string   `csv:"iata" json:"iata"`
+	ICAO      string   `csv:"icao" json:"icao"`
+	Latitude  *float64 `csv:"latitude" json:"latitude,omitempty"`
+	Longitude *float64 `csv:"longitude" json:"longitude,omitempty"`
+	Size      *int64   `csv:"size" json:"size,omitempty"`
+	Label     string   `csv:"label" json:"label"`
+}
+
+type SearchOperatorRecord struct {
+	Name       string `csv:"name" json:"name"`
+	IATA       string `csv:"iata" json:"iata"`
+	ICAO       string `csv:"icao" json:"icao"`
+	OperatorID *int64 `csv:"operator_id" json:"operator_id,omitempty"`
+	Label      string `csv:"label" json:"label"`
+}
+
+type SearchAircraftRecord struct {
+	Registration string  `csv:"registration" json:"registration"`
+	Typecode     *string `csv:"typecode" json:"typecode,omitempty"`
+	ICAO24       *int64  `csv:"icao24" json:"icao24,omitempty"`
+	Operator     *string `csv:"operator" json:"operator,omitempty"`
+	Label        string  `csv:"label" json:"label"`
+}
+
+type SearchLiveRecord struct {
+	FlightID     *int64   `csv:"flight_id" json:"flight_id,omitempty"`
+	Flight       *string  `csv:"flight" json:"flight,omitempty"`
+	Callsign     *string  `csv:"callsign" json:"callsign,omitempty"`
+	Registration *string  `csv:"registration" json:"registration,omitempty"`
+	Typecode     *string  `csv:"typecode" json:"typecode,omitempty"`
+	Operator     *string  `csv:"operator" json:"operator,omitempty"`
+	Origin       *string  `csv:"origin" json:"origin,omitempty"`
+	Destination  *string  `csv:"destination" json:"destination,omitempty"`
+	Route        *string  `csv:"route" json:"route,omitempty"`
+	Latitude     *float64 `csv:"latitude" json:"latitude,omitempty"`
+	Longitude    *float64 `csv:"longitude" json:"longitude,omitempty"`
+	Label        string   `csv:"label" json:"label"`
+}
+
+type SearchScheduleRecord struct {
+	Flight      string  `csv:"flight" json:"flight"`
+	Callsign    *string `csv:"callsign" json:"callsign,omitempty"`
+	Operator    *string `csv:"operator" json:"operator,omitempty"`
+	Origin      *string `csv:"origin" json:"origin,omitempty"`
+	Destination *string `csv:"destination" json:"destination,omitempty"`
+	Route       *string `csv:"route" json:"route,omitempty"`
+	Label       string  `csv:"label" json:"label"`
+}
+
+// ParseFind decodes a Find response into typed results grouped by kind.
+// Airport and operator codes come from labels such as "Name (HKG / VHHH)";
+// live flight ids are converted from hex.
+func ParseFind(body []byte) (*SearchResults, error) {
+	var root struct {
+		Results []struct {
+			ID     string         `json:"id"`
+			Label  string         `json:"label"`
+			Type   string         `json:"type"`
+			Match  string         `json:"match"`
+			Detail map[string]any `json:"detail"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(body, &root); err != nil {
+		return nil, err
+	}
+	out := &SearchResults{}
+	for _, r := range root.Results {
+		d := r.Detail
+		e := SearchEntry{Kind: r.Type, ID: r.ID, Label: r.Label, Match: r.Match}
+		out.All = append(out.All, e)
+		switch r.Type {
+		case SearchAirport:
+			name, iata, icao := splitSearchLabel(r.Label)
+			out.Airports = append(out.Airports, SearchAirportRecord{
+				Name: name, IATA: firstNonEmpty(iata, r.ID), ICAO: icao,
+				Latitude: detailFloat(d, "lat"), Longitude: detailFloat(d, "lon"), Size: detailInt(d, "size"),
+				Label: r.Label,
+			})
+		case SearchOperator:
+			name, iata, icao := splitSearchLabel(r.Label)
+			if s := detailString(d, "iata"); s != nil && iata == "" {
+				iata = *s
+			}
+			out.Operators = append(out.Operators, SearchOperatorRecord{
+				Name: name, IATA: iata, ICAO: firstNonEmpty(icao, r.ID),
+				OperatorID: detailInt(d, "operator_id"), Label: r.Label,
+			})
+		case SearchAircraft:
+			rec := SearchAircraftRecord{
+				Registration: r.ID, Typecode: detailString(d, "equip"),
+				Operator: detailString(d, "operator"), Label: r.Label,
+			}
+			if h := detailString(d, "hex"); h != nil {
+				if n, err := strconv.ParseInt(*h, 16, 64); err == nil {
+					rec.ICAO24 = &n
+				}
+			}
+			out.Aircraft = append(out.Aircraft, rec)
+		case SearchLive:
+			rec := SearchLiveRecord{
+				Flight: detailString(d, "flight"), Callsign: detailString(d, "callsign"),
+				Registration: detailString(d, "reg"), Typecode: detailString(d, "ac_type"),
+				Operator: detailString(d, "operator"), Origin: detailString(d, "schd_from"),
+				Destination: detailString(d, "schd_to"), Route: detailString(d, "route"),
+				Latitude: detailFloat(d, "lat"), Longitude: detailFloat(d, "lon"), Label: r.Label,
+			}
+			if n, err := strconv.ParseInt(r.ID, 16, 64); err == nil {
+				rec.FlightID = &n
+			}
+			out.Live = append(out.Live, rec)
+		case SearchSchedule:
+			out.Schedule = append(out.Schedule, SearchScheduleRecord{
+				Flight: firstNonEmpty(strValue(detailString(d, "flight")), r.ID), Callsign: detailString(d, "callsign"),
+				Operator: detailString(d, "operator"), Origin: detailString(d, "schd_from"),
+				Destination: detailString(d, "schd_to"), Route: detailString(d, "route"), Label: r.Label,
+			})
+		default:
+			out.Other = append(out.Other, e)
+		}
+	}
+	return out, nil
+}
+
+// splitSearchLabel splits "Name (AAA / BBBB)" into its name and codes.
+func splitSearchLabel(label string) (name, a, b string) {
+	i := strings.LastIndex(label, "(")
+	if i < 0 || !strings.HasSuffix(label, ")") {
+		return label, "", ""
+	}
+	name = strings.TrimSpace(label[:i])
+	codes := strings.Split(label[i+1:len(label)-1], "/")
+	a = strings.TrimSpace(codes[0])
+	if len(codes) > 1 {
+		b = strings.TrimSpace(codes[1])
+	}
+	return name, a, b
+}
+
+func detailString(d map[string]any, k string) *string {
+	if s, ok := d[k].(string); ok && s != "" {
+		return &s
+	}
+	return nil
+}
+
+func detailFloat(d map[string]any, k string) *float64 {
+	switch v := d[k].(type) {
+	case float64:
+		return &v
+	case string:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return &f
+		}
+	}
+	return nil
+}
+
+func detailInt(d map[string]any, k string) *int64 {
+	if f := detailFloat(d, k); f != nil {
+		n := int64(*f)
+		return &n
+	}
+	return nil
+}
+
+func strValue(p *string) string {
+	if p == nil {
+		return ""
+	}
+	return *p
+}
+
 // ---- helpers ----
 
 func firstNonEmpty(a, b string) string {
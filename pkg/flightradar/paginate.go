@@ -0,0 +1,165 @@
+package flightradar
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"iter"
+	"net/http"
+)
+
+// FlightListAll yields every flight of a registration or flight number,
+// newest first. It follows the upstream page metadata and, once a timestamp
+// has no more pages, moves the timestamp cursor to the oldest scheduled
+// departure seen and starts again from page 1, so it walks back through all
+// the history the account can see. p.TimestampS sets the starting cursor
+// (flights before that time) and p.Page the first page. maxPages bounds the
+// number of requests (0 means no limit). A fetch error is yielded and ends
+// the sequence.
+func (c *Client) FlightListAll(ctx context.Context, p FlightListParams, maxPages int) iter.Seq2[FlightListRecord, error] {
+	return func(yield func(FlightListRecord, error) bool) {
+		if err := p.validate(); err != nil {
+			yield(FlightListRecord{}, err)
+			return
+		}
+		seen := map[int64]bool{}
+		for pages := 0; maxPages <= 0 || pages < maxPages; pages++ {
+			resp, err := c.FlightList(ctx, p)
+			if err != nil {
+				yield(FlightListRecord{}, err)
+				return
+			}
+			body, err := readPage(resp)
+			if err != nil {
+				yield(FlightListRecord{}, err)
+				return
+			}
+			recs, err := ParseFlightList(body)
+			if err != nil {
+				yield(FlightListRecord{}, err)
+				return
+			}
+			var page struct {
+				Result struct {
+					Response struct {
+						Page struct {
+							Current int  `json:"current"`
+							More    bool `json:"more"`
+						} `json:"page"`
+					} `json:"response"`
+				} `json:"result"`
+			}
+			_ = json.Unmarshal(body, &page)
+
+			fresh := 0
+			var oldest *int64
+			for _, r := range recs {
+				if r.STOD != nil && (oldest == nil || *r.STOD < *oldest) {
+					oldest = r.STOD
+				}
+				if r.FlightID != nil {
+					if seen[*r.FlightID] {
+						continue
+					}
+					seen[*r.FlightID] = true
+				}
+				fresh++
+				if !yield(r, nil) {
+					return
+				}
+			}
+			switch {
+			case page.Result.Response.Page.More && len(recs) > 0:
+				p.Page++
+			case fresh > 0 && oldest != nil && (p.TimestampS == nil || *oldest/1000 < *p.TimestampS):
+				// Records are in ms; the cursor is in seconds.
+				ts := *oldest / 1000
+				p.TimestampS, p.Page = &ts, 1
+			default:
+				return
+			}
+		}
+	}
+}
+
+// AirportListAll yields every schedule entry of an airport board, walking
+// pages from p.Page up to the last page reported by the server. Entries are
+// deduplicated by flight id since boards move while they are paged. maxPages
+// bounds the number of requests (0 means no limit). A fetch error is yielded
+// and ends the sequence.
+func (c *Client) AirportListAll(ctx context.Context, p AirportListParams, maxPages int) iter.Seq2[AirportScheduleRecord, error] {
+	return func(yield func(AirportScheduleRecord, error) bool) {
+		if p.Mode == "" {
+			p.Mode = AirportArrivals
+		}
+		p.Page = zeroDefault(p.Page, 1)
+		if p.TimestampS == nil {
+			// Pin the board so every page sees the same schedule.
+			now := UnixNow()
+			p.TimestampS = &now
+		}
+		seen := map[int64]bool{}
+		for pages := 0; maxPages <= 0 || pages < maxPages; pages++ {
+			resp, err := c.AirportList(ctx, p)
+			if err != nil {
+				yield(AirportScheduleRecord{}, err)
+				return
+			}
+			body, err := readPage(resp)
+			if err != nil {
+				yield(AirportScheduleRecord{}, err)
+				return
+			}
+			recs, err := ParseAirportList(body)
+			if err != nil {
+				yield(AirportScheduleRecord{}, err)
+				return
+			}
+			type block struct {
+				Page struct {
+					Current int `json:"current"`
+					Total   int `json:"total"`
+				} `json:"page"`
+			}
+			var root struct {
+				Result struct {
+					Response struct {
+						Airport struct {
+							PluginData struct {
+								Schedule map[string]block `json:"schedule"`
+							} `json:"pluginData"`
+						} `json:"airport"`
+					} `json:"response"`
+				} `json:"result"`
+			}
+			_ = json.Unmarshal(body, &root)
+			meta := root.Result.Response.Airport.PluginData.Schedule[string(p.Mode)].Page
+
+			for _, r := range recs {
+				if r.FlightID != nil {
+					if seen[*r.FlightID] {
+						continue
+					}
+					seen[*r.FlightID] = true
+				}
+				if !yield(r, nil) {
+					return
+				}
+			}
+			if len(recs) == 0 || meta.Current >= meta.Total {
+				return
+			}
+			p.Page = meta.Current + 1
+		}
+	}
+}
+
+// readPage reads a JSON list response, returning an *HTTPError for
+// non-2xx statuses.
+func readPage(resp *http.Response) ([]byte, error) {
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newHTTPError(resp)
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,622 @@
+package flightradar
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// Parquet constants from parquet.thrift.
+const (
+	pqBoolean   = 0
+	pqInt32     = 1
+	pqInt64     = 2
+	pqFloat     = 4
+	pqDouble    = 5
+	pqByteArray = 6
+
+	pqRequired = 0
+	pqOptional = 1
+
+	pqNoConverted = -1
+	pqUTF8        = 0
+	pqUint8       = 11
+	pqUint16      = 12
+	pqUint32      = 13
+	pqUint64      = 14
+	pqInt8        = 15
+	pqInt16       = 16
+
+	pqPlain = 0
+	pqRLE   = 3
+
+	pqUncompressed = 0
+	pqGzip         = 2
+
+	pqDataPage       = 0
+	pqDictionaryPage = 2
+)
+
+var parquetMagic = []byte("PAR1")
+
+// WriteParquet writes a slice of structs as a single row group Parquet file.
+// Column names come from `csv` tags, then `json` tags, then field names;
+// nested structs are flattened into "parent.child" columns, as in WriteCSV.
+// Spark reads such names as struct access, so quote them with backticks
+// there (`origin.code`). Pointer fields (and fields below a pointer struct)
+// are nullable. Pages are PLAIN encoded and gzip compressed.
+func WriteParquet(w io.Writer, slice any) error {
+	rv := reflect.ValueOf(slice)
+	if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() != reflect.Struct {
+		return errors.New("parquet: want a slice of structs")
+	}
+	cols := parquetColumns(rv.Type().Elem(), "", nil, false)
+	if len(cols) == 0 {
+		return errors.New("parquet: no supported columns")
+	}
+	cw := &countWriter{w: w}
+	if _, err := cw.Write(parquetMagic); err != nil {
+		return err
+	}
+	rows := rv.Len()
+	var chunks []pqChunk
+	if rows > 0 {
+		for _, col := range cols {
+			ch, err := writeParquetColumn(cw, rv, col)
+			if err != nil {
+				return err
+			}
+			chunks = append(chunks, ch)
+		}
+	}
+	meta := parquetFooter(cols, chunks, rows)
+	if _, err := cw.Write(meta); err != nil {
+		return err
+	}
+	var tail [4]byte
+	binary.LittleEndian.PutUint32(tail[:], uint32(len(meta)))
+	if _, err := cw.Write(tail[:]); err != nil {
+		return err
+	}
+	_, err := cw.Write(parquetMagic)
+	return err
+}
+
+// ReadParquet reads a file written by WriteParquet into out, a pointer to a
+// slice of structs. Columns are matched by name; dictionary pages and nested
+// schemas are not supported.
+func ReadParquet(r io.Reader, out any) error {
+	pv := reflect.ValueOf(out)
+	if pv.Kind() != reflect.Pointer || pv.Elem().Kind() != reflect.Slice || pv.Elem().Type().Elem().Kind() != reflect.Struct {
+		return errors.New("parquet: out must be a pointer to a slice of structs")
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	n := len(data)
+	if n < 12 || !bytes.Equal(data[:4], parquetMagic) || !bytes.Equal(data[n-4:], parquetMagic) {
+		return errors.New("parquet: not a parquet file")
+	}
+	metaLen := int(binary.LittleEndian.Uint32(data[n-8 : n-4]))
+	if metaLen > n-12 {
+		return errors.New("parquet: invalid footer length")
+	}
+	meta, err := newThriftReader(data[n-8-metaLen : n-8]).readStruct()
+	if err != nil {
+		return fmt.Errorf("parquet: footer: %w", err)
+	}
+
+	sv := pv.Elem()
+	byName := map[string]pqColumn{}
+	for _, col := range parquetColumns(sv.Type().Elem(), "", nil, false) {
+		byName[col.name] = col
+	}
+	schema := meta.list(2)
+	var leaves []tStruct
+	for i, e := range schema {
+		el, _ := e.(tStruct)
+		if i == 0 {
+			continue
+		}
+		if el.int(5) > 0 {
+			return errors.New("parquet: nested schemas are not supported")
+		}
+		leaves = append(leaves, el)
+	}
+	for _, g := range meta.list(4) {
+		rg, _ := g.(tStruct)
+		rows := int(rg.int(3))
+		base := sv.Len()
+		sv.Set(reflect.AppendSlice(sv, reflect.MakeSlice(sv.Type(), rows, rows)))
+		for i, c := range rg.list(1) {
+			if i >= len(leaves) {
+				return errors.New("parquet: more column chunks than schema columns")
+			}
+			col, ok := byName[string(leaves[i].bytes(4))]
+			if !ok {
+				continue
+			}
+			cs, _ := c.(tStruct)
+			cm := cs.strct(3)
+			if cm == nil {
+				return errors.New("parquet: column chunk without metadata")
+			}
+			if _, ok := cm[11]; ok {
+				return errors.New("parquet: dictionary pages are not supported")
+			}
+			if int32(cm.int(1)) != col.typ {
+				return fmt.Errorf("parquet: column %q has physical type %d, want %d", col.name, cm.int(1), col.typ)
+			}
+			off, size := cm.int(9), cm.int(7)
+			if off < 0 || size < 0 || off+size > int64(n) {
+				return fmt.Errorf("parquet: column %q out of bounds", col.name)
+			}
+			optional := leaves[i].int(3) == pqOptional
+			err := readParquetColumn(data[off:off+size], int(cm.int(4)), optional, col, func(row int, v any) error {
+				if row >= rows {
+					return errors.New("parquet: more values than rows")
+				}
+				return setParquetValue(fieldByPath(sv.Index(base+row), col.index, true), col.typ, v)
+			})
+			if err != nil {
+				return fmt.Errorf("parquet: column %q: %w", col.name, err)
+			}
+		}
+	}
+	return nil
+}
+
+type pqColumn struct {
+	name      string
+	index     []int
+	optional  bool
+	typ       int32
+	converted int32
+}
+
+func parquetColumns(t reflect.Type, prefix string, index []int, optional bool) []pqColumn {
+	var cols []pqColumn
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		} // unexported
+		name := parquetName(f)
+		if name == "" {
+			continue
+		}
+		idx := append(append([]int(nil), index...), i)
+		ft, opt := f.Type, optional
+		if ft.Kind() == reflect.Pointer {
+			ft, opt = ft.Elem(), true
+		}
+		if ft.Kind() == reflect.Struct {
+			cols = append(cols, parquetColumns(ft, prefix+name+".", idx, opt)...)
+			continue
+		}
+		typ, conv, ok := parquetType(ft.Kind())
+		if !ok {
+			continue
+		}
+		cols = append(cols, pqColumn{name: prefix + name, index: idx, optional: opt, typ: typ, converted: conv})
+	}
+	return cols
+}
+
+// parquetName returns the column name for f, or "" if it is skipped.
+func parquetName(f reflect.StructField) string {
+	if tag := f.Tag.Get("csv"); tag != "" {
+		if tag == "-" {
+			return ""
+		}
+		return tag
+	}
+	if tag, _, _ := strings.Cut(f.Tag.Get("json"), ","); tag != "" {
+		if tag == "-" {
+			return ""
+		}
+		return tag
+	}
+	return f.Name
+}
+
+func parquetType(k reflect.Kind) (typ, converted int32, ok bool) {
+	switch k {
+	case reflect.Bool:
+		return pqBoolean, pqNoConverted, true
+	case reflect.Int8:
+		return pqInt32, pqInt8, true
+	case reflect.Int16:
+		return pqInt32, pqInt16, true
+	case reflect.Int32:
+		return pqInt32, pqNoConverted, true
+	case reflect.Int, reflect.Int64:
+		return pqInt64, pqNoConverted, true
+	case reflect.Uint8:
+		return pqInt32, pqUint8, true
+	case reflect.Uint16:
+		return pqInt32, pqUint16, true
+	case reflect.Uint32:
+		return pqInt32, pqUint32, true
+	case reflect.Uint, reflect.Uint64:
+		return pqInt64, pqUint64, true
+	case reflect.Float32:
+		return pqFloat, pqNoConverted, true
+	case reflect.Float64:
+		return pqDouble, pqNoConverted, true
+	case reflect.String:
+		return pqByteArray, pqUTF8, true
+	}
+	return 0, 0, false
+}
+
+type pqChunk struct {
+	offset           int64
+	compressedSize   int64
+	uncompressedSize int64
+}
+
+// writeParquetColumn writes the column as one gzip compressed data page.
+func writeParquetColumn(cw *countWriter, rv reflect.Value, col pqColumn) (pqChunk, error) {
+	rows := rv.Len()
+	var values bytes.Buffer
+	var bools []bool
+	levels := make([]byte, 0, rows)
+	for i := 0; i < rows; i++ {
+		v := fieldByPath(rv.Index(i), col.index, false)
+		if v.IsValid() && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v = reflect.Value{}
+			} else {
+				v = v.Elem()
+			}
+		}
+		if !v.IsValid() {
+			levels = append(levels, 0)
+			continue
+		}
+		levels = append(levels, 1)
+		switch col.typ {
+		case pqBoolean:
+			bools = append(bools, v.Bool())
+		case pqInt32:
+			_ = binary.Write(&values, binary.LittleEndian, int32(intOf(v)))
+		case pqInt64:
+			_ = binary.Write(&values, binary.LittleEndian, intOf(v))
+		case pqFloat:
+			_ = binary.Write(&values, binary.LittleEndian, math.Float32bits(float32(v.Float())))
+		case pqDouble:
+			_ = binary.Write(&values, binary.LittleEndian, math.Float64bits(v.Float()))
+		case pqByteArray:
+			_ = binary.Write(&values, binary.LittleEndian, uint32(v.Len()))
+			values.WriteString(v.String())
+		}
+	}
+	if col.typ == pqBoolean {
+		packed := make([]byte, (len(bools)+7)/8)
+		for i, b := range bools {
+			if b {
+				packed[i/8] |= 1 << (i % 8)
+			}
+		}
+		values.Write(packed)
+	}
+
+	var page bytes.Buffer
+	if col.optional {
+		rle := encodeLevels(levels)
+		_ = binary.Write(&page, binary.LittleEndian, uint32(len(rle)))
+		page.Write(rle)
+	}
+	page.Write(values.Bytes())
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	if _, err := zw.Write(page.Bytes()); err != nil {
+		return pqChunk{}, err
+	}
+	if err := zw.Close(); err != nil {
+		return pqChunk{}, err
+	}
+
+	var hdr thriftWriter
+	hdr.i32(1, pqDataPage)
+	hdr.i32(2, int32(page.Len()))
+	hdr.i32(3, int32(compressed.Len()))
+	hdr.beginStruct(5)
+	hdr.i32(1, int32(rows))
+	hdr.i32(2, pqPlain)
+	hdr.i32(3, pqRLE)
+	hdr.i32(4, pqRLE)
+	hdr.endStruct()
+	hdr.endStruct()
+
+	ch := pqChunk{offset: cw.n}
+	if _, err := cw.Write(hdr.buf.Bytes()); err != nil {
+		return ch, err
+	}
+	if _, err := cw.Write(compressed.Bytes()); err != nil {
+		return ch, err
+	}
+	ch.compressedSize = int64(hdr.buf.Len() + compressed.Len())
+	ch.uncompressedSize = int64(hdr.buf.Len() + page.Len())
+	return ch, nil
+}
+
+func intOf(v reflect.Value) int64 {
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint())
+	}
+	return v.Int()
+}
+
+// encodeLevels encodes 0/1 definition levels as RLE runs of bit width 1.
+func encodeLevels(levels []byte) []byte {
+	var out []byte
+	for i := 0; i < len(levels); {
+		j := i
+		for j < len(levels) && levels[j] == levels[i] {
+			j++
+		}
+		out = binary.AppendUvarint(out, uint64(j-i)<<1)
+		out = append(out, levels[i])
+		i = j
+	}
+	return out
+}
+
+func parquetFooter(cols []pqColumn, chunks []pqChunk, rows int) []byte {
+	var t thriftWriter
+	t.i32(1, 1)
+	t.listBegin(2, thriftStruct, len(cols)+1)
+	t.structElem()
+	t.binary(4, []byte("schema"))
+	t.i32(5, int32(len(cols)))
+	t.endStruct()
+	for _, col := range cols {
+		t.structElem()
+		t.i32(1, col.typ)
+		rep := int32(pqRequired)
+		if col.optional {
+			rep = pqOptional
+		}
+		t.i32(3, rep)
+		t.binary(4, []byte(col.name))
+		if col.converted != pqNoConverted {
+			t.i32(6, col.converted)
+		}
+		t.endStruct()
+	}
+	t.i64(3, int64(rows))
+	if len(chunks) == 0 {
+		t.listBegin(4, thriftStruct, 0)
+	} else {
+		t.listBegin(4, thriftStruct, 1)
+		t.structElem()
+		var total int64
+		t.listBegin(1, thriftStruct, len(chunks))
+		for i, ch := range chunks {
+			total += ch.uncompressedSize
+			t.structElem()
+			t.i64(2, ch.offset)
+			t.beginStruct(3)
+			t.i32(1, cols[i].typ)
+			t.listBegin(2, thriftI32, 2)
+			t.varint(zigzag(pqPlain))
+			t.varint(zigzag(pqRLE))
+			t.listBegin(3, thriftBinary, 1)
+			t.bytesValue([]byte(cols[i].name))
+			t.i32(4, pqGzip)
+			t.i64(5, int64(rows))
+			t.i64(6, ch.uncompressedSize)
+			t.i64(7, ch.compressedSize)
+			t.i64(9, ch.offset)
+			t.endStruct()
+			t.endStruct()
+		}
+		t.i64(2, total)
+		t.i64(3, int64(rows))
+		t.endStruct()
+	}
+	t.binary(6, []byte("fr24"))
+	t.endStruct()
+	return t.buf.Bytes()
+}
+
+// readParquetColumn decodes the data pages of a column chunk and calls set
+// for each non-null value with its row number.
+func readParquetColumn(chunk []byte, codec int, optional bool, col pqColumn, set func(row int, v any) error) error {
+	r := bytes.NewReader(chunk)
+	row := 0
+	for r.Len() > 0 {
+		tr := &thriftReader{r: r}
+		hdr, err := tr.readStruct()
+		if err != nil {
+			return err
+		}
+		size := int(hdr.int(3))
+		if size < 0 || size > r.Len() {
+			return errors.New("page out of bounds")
+		}
+		raw := make([]byte, size)
+		_, _ = io.ReadFull(r, raw)
+		switch hdr.int(1) {
+		case pqDataPage:
+		case pqDictionaryPage:
+			return errors.New("dictionary pages are not supported")
+		default:
+			continue
+		}
+		dph := hdr.strct(5)
+		if dph == nil || dph.int(2) != pqPlain {
+			return errors.New("only PLAIN data pages are supported")
+		}
+		page := raw
+		switch codec {
+		case pqUncompressed:
+		case pqGzip:
+			zr, err := gzip.NewReader(bytes.NewReader(raw))
+			if err != nil {
+				return err
+			}
+			if page, err = io.ReadAll(zr); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported compression codec %d", codec)
+		}
+		count := int(dph.int(1))
+		levels := make([]byte, count)
+		for i := range levels {
+			levels[i] = 1
+		}
+		if optional {
+			if len(page) < 4 {
+				return errors.New("truncated definition levels")
+			}
+			n := int(binary.LittleEndian.Uint32(page))
+			if n > len(page)-4 {
+				return errors.New("truncated definition levels")
+			}
+			if err := decodeLevels(page[4:4+n], levels); err != nil {
+				return err
+			}
+			page = page[4+n:]
+		}
+		vr := bytes.NewReader(page)
+		var bit int
+		for _, l := range levels {
+			if l == 0 {
+				row++
+				continue
+			}
+			var v any
+			var err error
+			switch col.typ {
+			case pqBoolean:
+				if bit/8 >= len(page) {
+					return io.ErrUnexpectedEOF
+				}
+				v = page[bit/8]&(1<<(bit%8)) != 0
+				bit++
+			case pqInt32:
+				var x int32
+				err = binary.Read(vr, binary.LittleEndian, &x)
+				v = int64(x)
+			case pqInt64:
+				var x int64
+				err = binary.Read(vr, binary.LittleEndian, &x)
+				v = x
+			case pqFloat:
+				var x uint32
+				err = binary.Read(vr, binary.LittleEndian, &x)
+				v = float64(math.Float32frombits(x))
+			case pqDouble:
+				var x uint64
+				err = binary.Read(vr, binary.LittleEndian, &x)
+				v = math.Float64frombits(x)
+			case pqByteArray:
+				var n uint32
+				if err = binary.Read(vr, binary.LittleEndian, &n); err == nil {
+					if int(n) > vr.Len() {
+						return io.ErrUnexpectedEOF
+					}
+					b := make([]byte, n)
+					_, _ = io.ReadFull(vr, b)
+					v = string(b)
+				}
+			}
+			if err != nil {
+				return err
+			}
+			if err := set(row, v); err != nil {
+				return err
+			}
+			row++
+		}
+	}
+	return nil
+}
+
+// decodeLevels decodes an RLE/bit-packed hybrid stream of bit width 1.
+func decodeLevels(data []byte, levels []byte) error {
+	r := bytes.NewReader(data)
+	i := 0
+	for i < len(levels) {
+		h, err := binary.ReadUvarint(r)
+		if err != nil {
+			return err
+		}
+		if h&1 == 0 {
+			v, err := r.ReadByte()
+			if err != nil {
+				return err
+			}
+			for n := int(h >> 1); n > 0 && i < len(levels); n-- {
+				levels[i] = v & 1
+				i++
+			}
+			continue
+		}
+		for groups := int(h >> 1); groups > 0; groups-- {
+			b, err := r.ReadByte()
+			if err != nil {
+				return err
+			}
+			for k := 0; k < 8 && i < len(levels); k++ {
+				levels[i] = (b >> k) & 1
+				i++
+			}
+		}
+	}
+	return nil
+}
+
+func setParquetValue(v reflect.Value, typ int32, x any) error {
+	if v.Kind() == reflect.Pointer {
+		p := reflect.New(v.Type().Elem())
+		if err := setParquetValue(p.Elem(), typ, x); err != nil {
+			return err
+		}
+		v.Set(p)
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(x.(bool))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(x.(int64))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n := x.(int64)
+		if typ == pqInt32 {
+			v.SetUint(uint64(uint32(n)))
+		} else {
+			v.SetUint(uint64(n))
+		}
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(x.(float64))
+	case reflect.String:
+		v.SetString(x.(string))
+	default:
+		return fmt.Errorf("unsupported field kind %s", v.Kind())
+	}
+	return nil
+}
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
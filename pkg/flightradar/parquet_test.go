@@ -0,0 +1,114 @@
+package flightradar
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+type pqInner struct {
+	Code  string   `csv:"code"`
+	Alt   *int32   `csv:"alt"`
+	Speed *float64 `csv:"speed"`
+}
+
+type pqRecord struct {
+	ID      uint32   `csv:"id"`
+	Big     uint64   `csv:"big"`
+	Neg     int64    `csv:"neg"`
+	Small   int8     `csv:"small"`
+	Ratio   float32  `csv:"ratio"`
+	OK      bool     `csv:"ok"`
+	Name    string   `csv:"name"`
+	Opt     *string  `csv:"opt"`
+	OptBool *bool    `csv:"opt_bool"`
+	Inner   pqInner  `csv:"inner"`
+	Ptr     *pqInner `csv:"ptr"`
+	Tags    []string `csv:"tags"`
+	Skipped string   `csv:"-"`
+	JSONTag int16    `json:"json_tag,omitempty"`
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestParquetRoundTrip(t *testing.T) {
+	in := []pqRecord{
+		{
+			ID: 1, Big: 1 << 40, Neg: -5, Small: -3, Ratio: 1.5, OK: true, Name: "first",
+			Opt: ptr("x"), OptBool: ptr(false),
+			Inner: pqInner{Code: "LEBB", Alt: ptr(int32(35000)), Speed: ptr(451.5)},
+			Ptr:   &pqInner{Code: "EGLL"},
+			Tags:  []string{"a"}, Skipped: "dropped", JSONTag: 7,
+		},
+		// nil pointers, a nil pointer struct and an empty slice
+		{ID: 2, Name: "", Tags: []string{}},
+		{ID: 3, Ptr: &pqInner{Alt: ptr(int32(0))}},
+	}
+	var buf bytes.Buffer
+	if err := WriteParquet(&buf, in); err != nil {
+		t.Fatal(err)
+	}
+	var out []pqRecord
+	if err := ReadParquet(bytes.NewReader(buf.Bytes()), &out); err != nil {
+		t.Fatal(err)
+	}
+	// Slices and csv:"-" fields are not written.
+	want := make([]pqRecord, len(in))
+	copy(want, in)
+	for i := range want {
+		want[i].Tags, want[i].Skipped = nil, ""
+	}
+	// A pointer struct whose columns are all null reads back as nil.
+	if !reflect.DeepEqual(out, want) {
+		t.Fatalf("round trip mismatch:\ngot  %+v\nwant %+v", out, want)
+	}
+}
+
+func TestParquetColumns(t *testing.T) {
+	var names []string
+	for _, c := range parquetColumns(reflect.TypeFor[pqRecord](), "", nil, false) {
+		names = append(names, c.name)
+	}
+	want := []string{"id", "big", "neg", "small", "ratio", "ok", "name", "opt", "opt_bool",
+		"inner.code", "inner.alt", "inner.speed", "ptr.code", "ptr.alt", "ptr.speed", "json_tag"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("columns = %v, want %v", names, want)
+	}
+}
+
+func TestParquetEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteParquet(&buf, []pqRecord{}); err != nil {
+		t.Fatal(err)
+	}
+	var out []pqRecord
+	if err := ReadParquet(&buf, &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 0 {
+		t.Errorf("got %d rows, want 0", len(out))
+	}
+}
+
+func TestParquetRejectsGarbage(t *testing.T) {
+	var out []pqRecord
+	for _, data := range [][]byte{nil, []byte("PAR1"), []byte("PAR1\x00\x00\x00\x00\xff\xff\xff\x7fPAR1")} {
+		if err := ReadParquet(bytes.NewReader(data), &out); err == nil {
+			t.Errorf("ReadParquet(%q): expected error", data)
+		}
+	}
+}
+
+func TestThriftRoundTrip(t *testing.T) {
+	meta := parquetFooter(parquetColumns(reflect.TypeFor[pqRecord](), "", nil, false), nil, 0)
+	s, err := newThriftReader(meta).readStruct()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := s.int(1); v != 1 {
+		t.Errorf("version = %d, want 1", v)
+	}
+	if n := len(s.list(2)); n != 17 { // root plus 16 leaves
+		t.Errorf("schema has %d elements, want 17", n)
+	}
+}
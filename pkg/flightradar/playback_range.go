@@ -0,0 +1,90 @@
+package flightradar
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"time"
+
+	pb "github.com/igolaizola/fr24/pkg/proto"
+)
+
+// PlaybackRangeParams describes a walk through historic live feed snapshots.
+type PlaybackRangeParams struct {
+	LiveFeed LiveFeedParams
+	From, To time.Time
+	// Step between snapshots (default 60s).
+	Step time.Duration
+	// Duration is the prefetch window of each snapshot in seconds (default 7).
+	Duration int32
+	// Tiled fetches each snapshot with a LiveFeedScanner over the bounding box.
+	Tiled       bool
+	Concurrency int
+	// Skip, if set, is called for each timestamp (unix seconds); returning
+	// true skips the fetch. Use it to resume an interrupted archive.
+	Skip func(ts int64) bool
+}
+
+// PlaybackSnapshot is one historic live feed snapshot.
+type PlaybackSnapshot struct {
+	Timestamp int64
+	Flights   []*pb.Flight
+}
+
+// PlaybackRange yields a snapshot for every step from From to To inclusive.
+// A fetch error is yielded and ends the sequence.
+func (c *Client) PlaybackRange(ctx context.Context, p PlaybackRangeParams) iter.Seq2[PlaybackSnapshot, error] {
+	return func(yield func(PlaybackSnapshot, error) bool) {
+		step := p.Step
+		if step <= 0 {
+			step = time.Minute
+		}
+		if p.From.IsZero() || p.To.IsZero() || p.To.Before(p.From) {
+			yield(PlaybackSnapshot{}, errors.New("playback range: From and To are required and To must not be before From"))
+			return
+		}
+		var scanner *LiveFeedScanner
+		if p.Tiled {
+			scanner = NewLiveFeedScanner(c, p.LiveFeed)
+			scanner.Concurrency = p.Concurrency
+		}
+		for t := p.From; !t.After(p.To); t = t.Add(step) {
+			if err := ctx.Err(); err != nil {
+				yield(PlaybackSnapshot{}, err)
+				return
+			}
+			ts := t.Unix()
+			if p.Skip != nil && p.Skip(ts) {
+				continue
+			}
+			snap := PlaybackSnapshot{Timestamp: ts}
+			if scanner != nil {
+				res, err := scanner.ScanPlayback(ctx, p.LiveFeed.BoundingBox, int32(ts), p.Duration)
+				if err != nil {
+					yield(snap, err)
+					return
+				}
+				snap.Flights = res.Flights
+			} else {
+				resp, err := c.PlaybackFeed(ctx, LiveFeedPlaybackParams{LiveFeed: p.LiveFeed, Timestamp: int32(ts), Duration: p.Duration})
+				if err != nil {
+					yield(snap, err)
+					return
+				}
+				snap.Flights = resp.GetLiveFeedResponse().GetFlightsList()
+			}
+			if !yield(snap, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Records flattens the snapshot flights.
+func (s PlaybackSnapshot) Records() []LiveFeedFlightRecord {
+	out := make([]LiveFeedFlightRecord, 0, len(s.Flights))
+	for _, f := range s.Flights {
+		out = append(out, LiveFeedFlightToRecord(f))
+	}
+	return out
+}
@@ -0,0 +1,150 @@
+package flightradar
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit is a token bucket: RPS requests per second on average, with up
+// to Burst requests at once. A non-positive RPS means unlimited.
+type RateLimit struct {
+	RPS   float64 `json:"rps"`
+	Burst int     `json:"burst"`
+}
+
+// RateLimits are the budgets of each endpoint family. Every family has its
+// own bucket, shared by all goroutines using the client.
+type RateLimits struct {
+	// GRPC limits gRPC-web feed calls, including opening streams.
+	GRPC RateLimit `json:"grpc"`
+	// API limits the JSON endpoints (flight list, airport, playback, find).
+	API RateLimit `json:"api"`
+	// Login limits username/password logins.
+	Login RateLimit `json:"login"`
+}
+
+// DefaultRateLimits are conservative budgets for batch jobs and scans.
+var DefaultRateLimits = RateLimits{
+	GRPC:  RateLimit{RPS: 5, Burst: 10},
+	API:   RateLimit{RPS: 2, Burst: 5},
+	Login: RateLimit{RPS: 1.0 / 60, Burst: 2},
+}
+
+// Endpoint families used for rate limiting and debug logs.
+const (
+	familyGRPC  = "grpc"
+	familyAPI   = "api"
+	familyLogin = "login"
+)
+
+// WithRateLimits sets the per-family request budgets, replacing any previous
+// buckets. The zero value (the default) is unlimited.
+func (c *Client) WithRateLimits(l RateLimits) *Client {
+	c.limiters = map[string]*limiter{
+		familyGRPC:  newLimiter(l.GRPC),
+		familyAPI:   newLimiter(l.API),
+		familyLogin: newLimiter(l.Login),
+	}
+	return c
+}
+
+// WithDebugLog logs rate limit waits and other diagnostics to l.
+func (c *Client) WithDebugLog(l *log.Logger) *Client {
+	c.debug = l
+	return c
+}
+
+func (c *Client) debugf(format string, args ...any) {
+	if c.debug != nil {
+		c.debug.Printf(format, args...)
+	}
+}
+
+// wait blocks until the family's budget allows one more request.
+func (c *Client) wait(ctx context.Context, family string) error {
+	l := c.limiters[family]
+	if l == nil {
+		return nil
+	}
+	d := l.reserve(time.Now())
+	if d <= 0 {
+		return nil
+	}
+	c.debugf("ratelimit: %s waiting %s", family, d.Round(time.Millisecond))
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		l.cancel()
+		return ctx.Err()
+	}
+}
+
+// limiter is a token bucket safe for concurrent use. Tokens may go negative:
+// each reservation queues behind the earlier ones.
+type limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newLimiter returns nil, meaning unlimited, for a non-positive rate.
+func newLimiter(l RateLimit) *limiter {
+	if l.RPS <= 0 {
+		return nil
+	}
+	burst := float64(max(l.Burst, 1))
+	return &limiter{rate: l.RPS, burst: burst, tokens: burst}
+}
+
+// reserve takes a token and returns how long to wait before using it.
+func (l *limiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.last.IsZero() {
+		l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// cancel returns a reserved token that was not used.
+func (l *limiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens = min(l.burst, l.tokens+1)
+}
+
+// RateLimitsFromEnvOrConfig reads budgets from the environment
+// (fr24_grpc_rps, fr24_grpc_burst, fr24_api_rps, ...) or the [global] section
+// of the config file (grpc_rps, grpc_burst, api_rps, api_burst, login_rps,
+// login_burst); the environment wins. Unset or invalid values keep those of
+// def.
+func RateLimitsFromEnvOrConfig(def RateLimits) RateLimits {
+	cfg := readConfig()
+	read := func(family string, l RateLimit) RateLimit {
+		if v, err := strconv.ParseFloat(envOrConfig(cfg, family+"_rps"), 64); err == nil {
+			l.RPS = v
+		}
+		if v, err := strconv.Atoi(envOrConfig(cfg, family+"_burst")); err == nil {
+			l.Burst = v
+		}
+		return l
+	}
+	return RateLimits{
+		GRPC:  read(familyGRPC, def.GRPC),
+		API:   read(familyAPI, def.API),
+		Login: read(familyLogin, def.Login),
+	}
+}
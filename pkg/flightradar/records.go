@@ -68,3 +68,76 @@ type PlaybackFlightRecord struct {
 	Callsign           string  `csv:"callsign" json:"callsign"`
 	Squawk             int32   `csv:"squawk" json:"squawk"`
 }
+
+type FollowFlightRecord struct {
+	// live position
+	TimestampMS   uint64        `csv:"timestamp_ms" json:"timestamp_ms"`
+	FlightID      uint32        `csv:"flightid" json:"flightid"`
+	Latitude      float32       `csv:"latitude" json:"latitude"`
+	Longitude     float32       `csv:"longitude" json:"longitude"`
+	Track         int32         `csv:"track" json:"track"`
+	Altitude      int32         `csv:"altitude" json:"altitude"`
+	GroundSpeed   int32         `csv:"ground_speed" json:"ground_speed"`
+	VerticalSpeed int32         `csv:"vertical_speed" json:"vertical_speed"`
+	OnGround      bool          `csv:"on_ground" json:"on_ground"`
+	Callsign      string        `csv:"callsign" json:"callsign"`
+	Squawk        int32         `csv:"squawk" json:"squawk"`
+	Source        pb.DataSource `csv:"source" json:"source"`
+	// aircraft and schedule
+	ICAOAddress  uint32 `csv:"icao_address" json:"icao_address"`
+	Reg          string `csv:"reg" json:"reg"`
+	Typecode     string `csv:"typecode" json:"typecode"`
+	FlightNumber string `csv:"flight_number" json:"flight_number"`
+	Origin       string `csv:"origin" json:"origin"`
+	Destination  string `csv:"destination" json:"destination"`
+	// progress
+	FlightStage       pb.FlightStage `csv:"flight_stage" json:"flight_stage"`
+	DelayStatus       pb.DelayStatus `csv:"delay_status" json:"delay_status"`
+	ProgressPct       uint32         `csv:"progress_pct" json:"progress_pct"`
+	TraversedDistance uint32         `csv:"traversed_distance" json:"traversed_distance"`
+	RemainingDistance uint32         `csv:"remaining_distance" json:"remaining_distance"`
+	ElapsedTime       uint32         `csv:"elapsed_time" json:"elapsed_time"`
+	RemainingTime     uint32         `csv:"remaining_time" json:"remaining_time"`
+	ETA               uint32         `csv:"eta" json:"eta"`
+	// trail points received in this frame (full trail first, deltas after)
+	Trail []TrailPointRecord `csv:"-" json:"trail"`
+}
+
+type TrailPointRecord struct {
+	Timestamp     uint64  `csv:"timestamp" json:"timestamp"`
+	Latitude      float32 `csv:"latitude" json:"latitude"`
+	Longitude     float32 `csv:"longitude" json:"longitude"`
+	Altitude      int32   `csv:"altitude" json:"altitude"`
+	GroundSpeed   uint32  `csv:"ground_speed" json:"ground_speed"`
+	Track         uint32  `csv:"track" json:"track"`
+	VerticalSpeed int32   `csv:"vertical_speed" json:"vertical_speed"`
+}
+
+// TrailRecord is one point of a live or historic trail.
+type TrailRecord struct {
+	Timestamp     uint64        `csv:"timestamp" json:"timestamp"`
+	Latitude      float32       `csv:"latitude" json:"latitude"`
+	Longitude     float32       `csv:"longitude" json:"longitude"`
+	Altitude      int32         `csv:"altitude" json:"altitude"`
+	GroundSpeed   uint32        `csv:"ground_speed" json:"ground_speed"`
+	Track         uint32        `csv:"track" json:"track"`
+	VerticalSpeed int32         `csv:"vertical_speed" json:"vertical_speed"`
+	Squawk        uint32        `csv:"squawk" json:"squawk"`
+	Source        pb.DataSource `csv:"source" json:"source"`
+	Callsign      string        `csv:"callsign" json:"callsign"`
+}
+
+// SearchIndexRecord is one live flight of the search index.
+type SearchIndexRecord struct {
+	FlightID     uint32  `csv:"flightid" json:"flightid"`
+	Callsign     string  `csv:"callsign" json:"callsign"`
+	Flight       string  `csv:"flight" json:"flight"`
+	Registration string  `csv:"registration" json:"registration"`
+	Typecode     string  `csv:"typecode" json:"typecode"`
+	Operator     string  `csv:"operator" json:"operator"`
+	Origin       string  `csv:"origin" json:"origin"`
+	Destination  string  `csv:"destination" json:"destination"`
+	Latitude     float32 `csv:"latitude" json:"latitude"`
+	Longitude    float32 `csv:"longitude" json:"longitude"`
+	Restricted   bool    `csv:"restricted" json:"restricted"`
+}
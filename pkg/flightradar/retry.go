@@ -0,0 +1,200 @@
+package flightradar
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how transient failures are retried. Only idempotent
+// calls are retried: unary gRPC RPCs and JSON GETs. Server streams and login
+// always make a single attempt, as repeating them could duplicate data or
+// lock the account.
+//
+// A call is retried on HTTP 429, 502, 503 and 504, on connection resets,
+// refusals and timeouts, and on a gRPC UNAVAILABLE status.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts including the first one. Values
+	// below 2 disable retries.
+	MaxAttempts int
+	// BaseDelay is the backoff before the first retry. It doubles on every
+	// retry up to MaxDelay, and each wait is jittered between half and all
+	// of it.
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff. A Retry-After header is honoured when it is
+	// not longer than MaxDelay; longer waits give up and return the response.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy makes up to 4 attempts, waiting about 0.5s, 1s and 2s.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 4, BaseDelay: 500 * time.Millisecond, MaxDelay: 30 * time.Second}
+
+// grpcUnavailable is the gRPC UNAVAILABLE status code.
+const grpcUnavailable = "14"
+
+// WithRetry sets the retry policy. The zero policy (the default) makes a
+// single attempt.
+func (c *Client) WithRetry(p RetryPolicy) *Client {
+	c.retry = p
+	return c
+}
+
+// retryable reports whether req is safe to repeat: a GET or a unary gRPC-web
+// call with a replayable body.
+func retryable(req *http.Request) bool {
+	switch {
+	case req.Method == http.MethodGet || req.Method == http.MethodHead:
+		return true
+	case strings.HasPrefix(req.Header.Get("Content-Type"), "application/grpc-web"):
+		return req.Body == nil || req.GetBody != nil
+	}
+	return false
+}
+
+// doRetry sends req, retrying transient failures according to the policy.
+func (c *Client) doRetry(ctx context.Context, req *http.Request) (*http.Response, error) {
+	p := c.retry
+	if p.MaxAttempts < 2 || !retryable(req) {
+		return c.send(ctx, req)
+	}
+	for attempt := 1; ; attempt++ {
+		r := req
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r = req.Clone(ctx)
+			r.Body = body
+		}
+		resp, err := c.send(ctx, r)
+		status := ""
+		if err == nil {
+			if status, err = grpcStatus(resp); err != nil {
+				resp = nil
+			}
+		}
+		wait, retry := p.shouldRetry(ctx, attempt, resp, status, err)
+		if !retry {
+			return resp, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			_ = resp.Body.Close()
+		}
+		t := time.NewTimer(wait)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// shouldRetry decides whether another attempt is due and how long to wait.
+// status is the gRPC status of the response, if any.
+func (p RetryPolicy) shouldRetry(ctx context.Context, attempt int, resp *http.Response, status string, err error) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts || ctx.Err() != nil {
+		return 0, false
+	}
+	switch {
+	case err != nil:
+		if !transientError(err) {
+			return 0, false
+		}
+	case resp.StatusCode == http.StatusTooManyRequests,
+		resp.StatusCode == http.StatusBadGateway,
+		resp.StatusCode == http.StatusServiceUnavailable,
+		resp.StatusCode == http.StatusGatewayTimeout:
+		if d, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			if p.MaxDelay > 0 && d > p.MaxDelay {
+				return 0, false
+			}
+			return d, true
+		}
+	case resp.StatusCode == http.StatusOK && status == grpcUnavailable:
+	default:
+		return 0, false
+	}
+	return p.backoff(attempt), true
+}
+
+// backoff returns the jittered wait before retry number attempt.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func retryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if s, err := strconv.Atoi(strings.TrimSpace(v)); err == nil && s >= 0 {
+		return time.Duration(s) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+// transientError reports whether a transport error is worth retrying.
+func transientError(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNABORTED) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return true
+	}
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
+
+// grpcStatus returns the gRPC status of a successful gRPC-web response:
+// the grpc-status header of a trailers-only response, or else the status of
+// the trailer frame. The body is read to find the trailer and replaced by
+// the buffered bytes.
+func grpcStatus(resp *http.Response) (string, error) {
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/grpc-web") {
+		return "", nil
+	}
+	if st := resp.Header.Get("grpc-status"); st != "" {
+		return st, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return "", err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	fr := NewFrameReader(bytes.NewReader(body))
+	for {
+		f, err := fr.Next()
+		if err != nil {
+			return "", nil
+		}
+		if f.IsTrailer() {
+			return parseTrailers(f.Payload).Status, nil
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package flightradar
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+
+	pb "github.com/igolaizola/fr24/pkg/proto"
+)
+
+// WorldBoundingBox covers the whole globe.
+var WorldBoundingBox = BoundingBox{South: -90, North: 90, West: -180, East: 180}
+
+// MaxLiveFeedLimit is the most flights the server returns per LiveFeed
+// request; anonymous sessions are capped there whatever Limit asks for.
+const MaxLiveFeedLimit = 1500
+
+// LiveFeedScanner fetches a large bounding box as a grid of tiles so that the
+// per-request flight limit does not silently drop aircraft. Tiles that come
+// back at the limit are split into quadrants and fetched again.
+type LiveFeedScanner struct {
+	Client *Client
+	// Params is the request template; its BoundingBox is replaced per tile.
+	// Limit is capped at MaxLiveFeedLimit (the default), since a tile
+	// truncated by the server below a larger limit would never be split.
+	Params LiveFeedParams
+	// TileSize is the initial tile edge in degrees (default 30).
+	TileSize float32
+	// Concurrency bounds in-flight requests (default 4).
+	Concurrency int
+	// MaxDepth bounds how many times a tile may be split (default 6).
+	MaxDepth int
+}
+
+// LiveFeedScanResult is the merged snapshot of a scan.
+type LiveFeedScanResult struct {
+	// Flights are deduplicated by flight id and sorted by it.
+	Flights []*pb.Flight
+	// Requests is the number of tiles fetched.
+	Requests int
+	// Truncated counts tiles that were still at the limit at MaxDepth, so
+	// the snapshot may be incomplete there.
+	Truncated int
+}
+
+// NewLiveFeedScanner returns a scanner with default tiling settings.
+func NewLiveFeedScanner(c *Client, p LiveFeedParams) *LiveFeedScanner {
+	return &LiveFeedScanner{Client: c, Params: p}
+}
+
+type scanTile struct {
+	bb    BoundingBox
+	depth int
+}
+
+// Scan fetches every tile covering bb and merges the results. The first
+// request error cancels the remaining tiles and is returned.
+func (s *LiveFeedScanner) Scan(ctx context.Context, bb BoundingBox) (*LiveFeedScanResult, error) {
+	return s.scan(ctx, bb, func(ctx context.Context, p LiveFeedParams) ([]*pb.Flight, error) {
+		resp, err := s.Client.LiveFeed(ctx, p)
+		return resp.GetFlightsList(), err
+	})
+}
+
+// ScanPlayback is like Scan but fetches the historic snapshot at ts (unix
+// seconds) with the given prefetch duration through the Playback RPC.
+func (s *LiveFeedScanner) ScanPlayback(ctx context.Context, bb BoundingBox, ts, duration int32) (*LiveFeedScanResult, error) {
+	return s.scan(ctx, bb, func(ctx context.Context, p LiveFeedParams) ([]*pb.Flight, error) {
+		resp, err := s.Client.PlaybackFeed(ctx, LiveFeedPlaybackParams{LiveFeed: p, Timestamp: ts, Duration: duration})
+		return resp.GetLiveFeedResponse().GetFlightsList(), err
+	})
+}
+
+func (s *LiveFeedScanner) scan(ctx context.Context, bb BoundingBox, fetchTile func(context.Context, LiveFeedParams) ([]*pb.Flight, error)) (*LiveFeedScanResult, error) {
+	if err := s.Params.validate(); err != nil {
+		return nil, err
+	}
+	size := s.TileSize
+	if size <= 0 {
+		size = 30
+	}
+	conc := s.Concurrency
+	if conc <= 0 {
+		conc = 4
+	}
+	maxDepth := s.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = 6
+	}
+	limit := s.Params.Limit
+	if limit <= 0 || limit > MaxLiveFeedLimit {
+		limit = MaxLiveFeedLimit
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		flights  = map[int32]*pb.Flight{}
+		res      LiveFeedScanResult
+	)
+	sem := make(chan struct{}, conc)
+
+	var fetch func(t scanTile)
+	fetch = func(t scanTile) {
+		defer wg.Done()
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+		p := s.Params
+		p.BoundingBox = t.bb
+		p.Limit = limit
+		got, err := fetchTile(ctx, p)
+		<-sem
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			if firstErr == nil && ctx.Err() == nil {
+				firstErr = err
+				cancel()
+			}
+			return
+		}
+		res.Requests++
+		for _, f := range got {
+			if prev, ok := flights[f.GetFlightid()]; !ok || f.GetTimestampMs() > prev.GetTimestampMs() {
+				flights[f.GetFlightid()] = f
+			}
+		}
+		if len(got) < int(limit) {
+			return
+		}
+		if t.depth >= maxDepth {
+			res.Truncated++
+			return
+		}
+		for _, q := range t.bb.quadrants() {
+			wg.Add(1)
+			go fetch(scanTile{bb: q, depth: t.depth + 1})
+		}
+	}
+
+	for _, t := range bb.grid(size) {
+		wg.Add(1)
+		go fetch(scanTile{bb: t})
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	res.Flights = make([]*pb.Flight, 0, len(flights))
+	for _, f := range flights {
+		res.Flights = append(res.Flights, f)
+	}
+	sort.Slice(res.Flights, func(i, j int) bool { return res.Flights[i].GetFlightid() < res.Flights[j].GetFlightid() })
+	return &res, nil
+}
+
+// grid splits the box into tiles of at most size degrees per side.
+func (b BoundingBox) grid(size float32) []BoundingBox {
+	rows := int(math.Ceil(float64((b.North - b.South) / size)))
+	cols := int(math.Ceil(float64((b.East - b.West) / size)))
+	rows, cols = max(rows, 1), max(cols, 1)
+	dLat := (b.North - b.South) / float32(rows)
+	dLon := (b.East - b.West) / float32(cols)
+	out := make([]BoundingBox, 0, rows*cols)
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			t := BoundingBox{
+				South: b.South + float32(r)*dLat,
+				North: b.South + float32(r+1)*dLat,
+				West:  b.West + float32(c)*dLon,
+				East:  b.West + float32(c+1)*dLon,
+			}
+			// avoid float drift on the outer edges
+			if r == rows-1 {
+				t.North = b.North
+			}
+			if c == cols-1 {
+				t.East = b.East
+			}
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// quadrants splits the box into four equal tiles.
+func (b BoundingBox) quadrants() []BoundingBox {
+	midLat := (b.South + b.North) / 2
+	midLon := (b.West + b.East) / 2
+	return []BoundingBox{
+		{South: b.South, North: midLat, West: b.West, East: midLon},
+		{South: b.South, North: midLat, West: midLon, East: b.East},
+		{South: midLat, North: b.North, West: b.West, East: midLon},
+		{South: midLat, North: b.North, West: midLon, East: b.East},
+	}
+}
@@ -0,0 +1,46 @@
+package flightradar
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/igolaizola/fr24/pkg/proto"
+)
+
+// TestScanSplitsServerCappedTiles checks that tiles truncated by the server
+// cap are split even when the caller asks for a larger limit.
+func TestScanSplitsServerCappedTiles(t *testing.T) {
+	var all []*pb.Flight
+	for i := 0; i < 4000; i++ {
+		all = append(all, &pb.Flight{
+			Flightid: int32(i + 1),
+			Lat:      40 + float32(i%80)*0.1,
+			Lon:      -5 + float32(i/80)*0.1,
+		})
+	}
+	server := func(_ context.Context, p LiveFeedParams) ([]*pb.Flight, error) {
+		limit := min(int(p.Limit), MaxLiveFeedLimit)
+		var got []*pb.Flight
+		bb := p.BoundingBox
+		for _, f := range all {
+			if f.Lat >= bb.South && f.Lat < bb.North && f.Lon >= bb.West && f.Lon < bb.East {
+				got = append(got, f)
+				if len(got) == limit {
+					break
+				}
+			}
+		}
+		return got, nil
+	}
+	s := &LiveFeedScanner{Params: LiveFeedParams{Limit: 5000}, TileSize: 30}
+	res, err := s.scan(context.Background(), WorldBoundingBox, server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Flights) != len(all) {
+		t.Fatalf("got %d flights, want %d", len(res.Flights), len(all))
+	}
+	if res.Truncated != 0 {
+		t.Errorf("Truncated = %d", res.Truncated)
+	}
+}
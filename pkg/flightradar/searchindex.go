@@ -0,0 +1,56 @@
+package flightradar
+
+import (
+	"path"
+	"strings"
+)
+
+// SearchIndexFilter selects search index records locally. Each non-empty
+// list must have a value matching the record; values are case-insensitive
+// and may use path.Match wildcards, e.g. "A35*". An empty filter matches
+// everything.
+type SearchIndexFilter struct {
+	Operators     []string
+	Types         []string
+	Origins       []string
+	Destinations  []string
+	Callsigns     []string
+	Registrations []string
+	Flights       []string
+}
+
+// Match reports whether r passes the filter.
+func (f SearchIndexFilter) Match(r SearchIndexRecord) bool {
+	return matchAny(f.Operators, r.Operator) &&
+		matchAny(f.Types, r.Typecode) &&
+		matchAny(f.Origins, r.Origin) &&
+		matchAny(f.Destinations, r.Destination) &&
+		matchAny(f.Callsigns, r.Callsign) &&
+		matchAny(f.Registrations, r.Registration) &&
+		matchAny(f.Flights, r.Flight)
+}
+
+// Filter returns the records that pass the filter.
+func (f SearchIndexFilter) Filter(recs []SearchIndexRecord) []SearchIndexRecord {
+	out := make([]SearchIndexRecord, 0, len(recs))
+	for _, r := range recs {
+		if f.Match(r) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func matchAny(patterns []string, v string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	v = strings.ToUpper(v)
+	for _, p := range patterns {
+		p = strings.ToUpper(p)
+		if ok, err := path.Match(p, v); ok || (err != nil && p == v) {
+			return true
+		}
+	}
+	return false
+}
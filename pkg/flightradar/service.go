@@ -58,6 +58,11 @@ func (s *ServiceFactory) Playback() *PlaybackService { return &PlaybackService{s
 type PlaybackResult struct {
 	Request  PlaybackParams
 	Response *http.Response
+
+	// body is the response body, read once and shared by Records and Flight.
+	body []byte
+	read bool
+	err  error
 }
 
 func (svc *PlaybackService) Fetch(ctx context.Context, p PlaybackParams) (*PlaybackResult, error) {
@@ -68,10 +73,30 @@ func (svc *PlaybackService) Fetch(ctx context.Context, p PlaybackParams) (*Playb
 	return &PlaybackResult{Request: p, Response: resp}, nil
 }
 func (r *PlaybackResult) Records() ([]PlaybackTrack, error) {
-    defer func() { _ = r.Response.Body.Close() }()
-	b, _ := io.ReadAll(r.Response.Body)
+	b, err := r.bytes()
+	if err != nil {
+		return nil, err
+	}
 	return ParsePlayback(b)
 }
+func (r *PlaybackResult) Flight() (*PlaybackFlight, error) {
+	b, err := r.bytes()
+	if err != nil {
+		return nil, err
+	}
+	return ParsePlaybackFlight(b)
+}
+
+// bytes reads and closes the response body on first use, so Records and
+// Flight can both be called on the same result.
+func (r *PlaybackResult) bytes() ([]byte, error) {
+	if !r.read {
+		r.read = true
+		r.body, r.err = io.ReadAll(r.Response.Body)
+		_ = r.Response.Body.Close()
+	}
+	return r.body, r.err
+}
 func (r *PlaybackResult) WriteCSV(path string) error {
 	recs, err := r.Records()
 	if err != nil {
@@ -107,8 +132,7 @@ func (svc *LiveFeedService) Fetch(ctx context.Context, p LiveFeedParams) (*LiveF
 }
 func (r *LiveFeedResult) Records() ([]LiveFeedFlightRecord, error) {
     defer func() { _ = r.Response.Body.Close() }()
-	b, _ := io.ReadAll(r.Response.Body)
-	msg, err := ParseLiveFeedGRPC(b)
+	msg, err := parseLiveFeedResponse(r.Response.Body, r.Response.Header)
 	if err != nil {
 		return nil, err
 	}
@@ -138,8 +162,7 @@ func (svc *NearestFlightsService) Fetch(ctx context.Context, p NearestFlightsPar
 }
 func (r *NearestFlightsResult) Records() ([]NearbyFlightRecord, error) {
     defer func() { _ = r.Response.Body.Close() }()
-	b, _ := io.ReadAll(r.Response.Body)
-	msg, err := ParseNearestFlightsGRPC(b)
+	msg, err := parseNearestFlightsResponse(r.Response.Body, r.Response.Header)
 	if err != nil {
 		return nil, err
 	}
@@ -167,8 +190,7 @@ func (svc *LiveFlightsStatusService) Fetch(ctx context.Context, p LiveFlightsSta
 }
 func (r *LiveFlightsStatusResult) Records() ([]LiveFlightsStatusRecord, error) {
     defer func() { _ = r.Response.Body.Close() }()
-	b, _ := io.ReadAll(r.Response.Body)
-	msg, err := ParseLiveFlightsStatusGRPC(b)
+	msg, err := parseLiveFlightsStatusResponse(r.Response.Body, r.Response.Header)
 	if err != nil {
 		return nil, err
 	}
@@ -194,8 +216,7 @@ func (svc *TopFlightsService) Fetch(ctx context.Context, p TopFlightsParams) (*T
 }
 func (r *TopFlightsResult) Records() ([]TopFlightRecord, error) {
     defer func() { _ = r.Response.Body.Close() }()
-	b, _ := io.ReadAll(r.Response.Body)
-	msg, err := ParseTopFlightsGRPC(b)
+	msg, err := parseTopFlightsResponse(r.Response.Body, r.Response.Header)
 	if err != nil {
 		return nil, err
 	}
@@ -206,6 +227,83 @@ func (r *TopFlightsResult) Records() ([]TopFlightRecord, error) {
 	return out, nil
 }
 
+// ---------- Live Trail (gRPC) ----------
+type LiveTrailService struct{ f *ServiceFactory }
+
+func (s *ServiceFactory) LiveTrail() *LiveTrailService { return &LiveTrailService{s} }
+
+type LiveTrailResult struct {
+	FlightID uint32
+	Response *http.Response
+}
+
+func (svc *LiveTrailService) Fetch(ctx context.Context, flightID uint32) (*LiveTrailResult, error) {
+	resp, err := svc.f.C.GrpcLiveTrail(ctx, flightID)
+	if err != nil {
+		return nil, err
+	}
+	return &LiveTrailResult{FlightID: flightID, Response: resp}, nil
+}
+func (r *LiveTrailResult) Records() ([]TrailRecord, error) {
+    defer func() { _ = r.Response.Body.Close() }()
+	msg, err := parseLiveTrailResponse(r.Response.Body, r.Response.Header)
+	if err != nil {
+		return nil, err
+	}
+	return TrailToRecords(msg.GetRadarRecordsList()), nil
+}
+
+// ---------- Historic Trail (gRPC) ----------
+type HistoricTrailService struct{ f *ServiceFactory }
+
+func (s *ServiceFactory) HistoricTrail() *HistoricTrailService { return &HistoricTrailService{s} }
+
+type HistoricTrailResult struct {
+	FlightID uint32
+	Response *http.Response
+}
+
+func (svc *HistoricTrailService) Fetch(ctx context.Context, flightID uint32) (*HistoricTrailResult, error) {
+	resp, err := svc.f.C.GrpcHistoricTrail(ctx, flightID)
+	if err != nil {
+		return nil, err
+	}
+	return &HistoricTrailResult{FlightID: flightID, Response: resp}, nil
+}
+func (r *HistoricTrailResult) Records() ([]TrailRecord, error) {
+    defer func() { _ = r.Response.Body.Close() }()
+	msg, err := parseHistoricTrailResponse(r.Response.Body, r.Response.Header)
+	if err != nil {
+		return nil, err
+	}
+	return TrailToRecords(msg.GetRadarRecordsList()), nil
+}
+
+// ---------- Search Index (gRPC) ----------
+type SearchIndexService struct{ f *ServiceFactory }
+
+func (s *ServiceFactory) SearchIndex() *SearchIndexService { return &SearchIndexService{s} }
+
+type SearchIndexResult struct {
+	Response *http.Response
+}
+
+func (svc *SearchIndexService) Fetch(ctx context.Context) (*SearchIndexResult, error) {
+	resp, err := svc.f.C.GrpcFetchSearchIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &SearchIndexResult{Response: resp}, nil
+}
+func (r *SearchIndexResult) Records() ([]SearchIndexRecord, error) {
+    defer func() { _ = r.Response.Body.Close() }()
+	msg, err := parseFetchSearchIndexResponse(r.Response.Body, r.Response.Header)
+	if err != nil {
+		return nil, err
+	}
+	return SearchIndexToRecords(msg), nil
+}
+
 // ---------- Flight Details (gRPC) ----------
 type FlightDetailsService struct{ f *ServiceFactory }
 
@@ -225,8 +323,7 @@ func (svc *FlightDetailsService) Fetch(ctx context.Context, p FlightDetailsParam
 }
 func (r *FlightDetailsResult) Record() (FlightDetailsRecord, error) {
     defer func() { _ = r.Response.Body.Close() }()
-	b, _ := io.ReadAll(r.Response.Body)
-	msg, err := ParseFlightDetailsGRPC(b)
+	msg, err := parseFlightDetailsResponse(r.Response.Body, r.Response.Header)
 	if err != nil {
 		return FlightDetailsRecord{}, err
 	}
@@ -252,8 +349,7 @@ func (svc *PlaybackFlightService) Fetch(ctx context.Context, p PlaybackFlightPar
 }
 func (r *PlaybackFlightResult) Record() (PlaybackFlightRecord, error) {
     defer func() { _ = r.Response.Body.Close() }()
-	b, _ := io.ReadAll(r.Response.Body)
-	msg, err := ParsePlaybackFlightGRPC(b)
+	msg, err := parsePlaybackFlightResponse(r.Response.Body, r.Response.Header)
 	if err != nil {
 		return PlaybackFlightRecord{}, err
 	}
@@ -281,6 +377,11 @@ func (r *FindResult) JSON(v any) error {
     defer func() { _ = r.Response.Body.Close() }()
 	return json.NewDecoder(r.Response.Body).Decode(v)
 }
+func (r *FindResult) Results() (*SearchResults, error) {
+    defer func() { _ = r.Response.Body.Close() }()
+	b, _ := io.ReadAll(r.Response.Body)
+	return ParseFind(b)
+}
 
 // ---------- Airport list (JSON) ----------
 type AirportListService struct{ f *ServiceFactory }
@@ -299,9 +400,10 @@ func (svc *AirportListService) Fetch(ctx context.Context, p AirportListParams) (
 	}
 	return &AirportListResult{Request: p, Response: resp}, nil
 }
-func (r *AirportListResult) JSON(v any) error {
+func (r *AirportListResult) Records() ([]AirportScheduleRecord, error) {
     defer func() { _ = r.Response.Body.Close() }()
-	return json.NewDecoder(r.Response.Body).Decode(v)
+	b, _ := io.ReadAll(r.Response.Body)
+	return ParseAirportList(b)
 }
 
 func dirOf(p string) string {
@@ -0,0 +1,32 @@
+package flightradar
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestPlaybackResultRecordsAndFlight(t *testing.T) {
+	body := `{"result":{"response":{"data":{"flight":{
+		"identification":{"callsign":"ABC123"},
+		"track":[{"timestamp":1700000000,"latitude":43.3,"longitude":-2.9,"squawk":"7700"}]}}}}}`
+	r := &PlaybackResult{Response: &http.Response{Body: io.NopCloser(strings.NewReader(body))}}
+	recs, err := r.Records()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 1 || recs[0].Timestamp != 1700000000 {
+		t.Fatalf("records: %+v", recs)
+	}
+	f, err := r.Flight()
+	if err != nil {
+		t.Fatalf("flight after records: %v", err)
+	}
+	if f.Callsign == nil || *f.Callsign != "ABC123" || len(f.Track) != 1 {
+		t.Fatalf("flight: %+v", f)
+	}
+	if recs, err := r.Records(); err != nil || len(recs) != 1 {
+		t.Fatalf("records again: %v, %v", recs, err)
+	}
+}
@@ -0,0 +1,232 @@
+package flightradar
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+)
+
+// Minimal Thrift compact protocol codec for Parquet metadata.
+
+const (
+	thriftTrue   = 1
+	thriftFalse  = 2
+	thriftByte   = 3
+	thriftI16    = 4
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftDouble = 7
+	thriftBinary = 8
+	thriftList   = 9
+	thriftSet    = 10
+	thriftMap    = 11
+	thriftStruct = 12
+)
+
+// thriftWriter encodes a struct; fields must be written in ascending id order
+// within each struct.
+type thriftWriter struct {
+	buf  bytes.Buffer
+	last []int16
+}
+
+func (w *thriftWriter) field(id int16, typ byte) {
+	if len(w.last) == 0 {
+		w.last = append(w.last, 0)
+	}
+	top := &w.last[len(w.last)-1]
+	if d := id - *top; d > 0 && d <= 15 {
+		w.buf.WriteByte(byte(d)<<4 | typ)
+	} else {
+		w.buf.WriteByte(typ)
+		w.varint(zigzag(int64(id)))
+	}
+	*top = id
+}
+
+func (w *thriftWriter) varint(v uint64) { w.buf.Write(binary.AppendUvarint(nil, v)) }
+
+func (w *thriftWriter) i32(id int16, v int32) {
+	w.field(id, thriftI32)
+	w.varint(zigzag(int64(v)))
+}
+
+func (w *thriftWriter) i64(id int16, v int64) {
+	w.field(id, thriftI64)
+	w.varint(zigzag(v))
+}
+
+func (w *thriftWriter) binary(id int16, b []byte) {
+	w.field(id, thriftBinary)
+	w.bytesValue(b)
+}
+
+func (w *thriftWriter) bytesValue(b []byte) {
+	w.varint(uint64(len(b)))
+	w.buf.Write(b)
+}
+
+func (w *thriftWriter) listBegin(id int16, elem byte, n int) {
+	w.field(id, thriftList)
+	if n < 15 {
+		w.buf.WriteByte(byte(n)<<4 | elem)
+		return
+	}
+	w.buf.WriteByte(0xf0 | elem)
+	w.varint(uint64(n))
+}
+
+func (w *thriftWriter) beginStruct(id int16) {
+	w.field(id, thriftStruct)
+	w.last = append(w.last, 0)
+}
+
+// structElem starts a struct that is a list element.
+func (w *thriftWriter) structElem() { w.last = append(w.last, 0) }
+
+func (w *thriftWriter) endStruct() {
+	w.buf.WriteByte(0)
+	if len(w.last) > 0 {
+		w.last = w.last[:len(w.last)-1]
+	}
+}
+
+func zigzag(n int64) uint64 { return uint64(n<<1) ^ uint64(n>>63) }
+
+// tStruct is a decoded struct keyed by field id. Integers decode to int64,
+// binary to []byte, lists to []any and structs to tStruct.
+type tStruct map[int16]any
+
+func (s tStruct) int(id int16) int64 { n, _ := s[id].(int64); return n }
+func (s tStruct) bytes(id int16) []byte {
+	b, _ := s[id].([]byte)
+	return b
+}
+func (s tStruct) list(id int16) []any { l, _ := s[id].([]any); return l }
+func (s tStruct) strct(id int16) tStruct {
+	st, _ := s[id].(tStruct)
+	return st
+}
+
+type thriftReader struct{ r *bytes.Reader }
+
+func newThriftReader(b []byte) *thriftReader { return &thriftReader{r: bytes.NewReader(b)} }
+
+var errThrift = errors.New("invalid thrift data")
+
+func (t *thriftReader) readStruct() (tStruct, error) {
+	out := tStruct{}
+	var last int16
+	for {
+		b, err := t.r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if b == 0 {
+			return out, nil
+		}
+		typ := b & 0x0f
+		id := last + int16(b>>4)
+		if b>>4 == 0 {
+			n, err := t.readInt()
+			if err != nil {
+				return nil, err
+			}
+			id = int16(n)
+		}
+		last = id
+		if typ == thriftTrue || typ == thriftFalse {
+			out[id] = typ == thriftTrue
+			continue
+		}
+		if out[id], err = t.readValue(typ); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (t *thriftReader) readInt() (int64, error) {
+	u, err := binary.ReadUvarint(t.r)
+	return int64(u>>1) ^ -int64(u&1), err
+}
+
+func (t *thriftReader) readSize() (int, error) {
+	u, err := binary.ReadUvarint(t.r)
+	if err != nil {
+		return 0, err
+	}
+	if u > uint64(t.r.Len()) {
+		return 0, errThrift
+	}
+	return int(u), nil
+}
+
+func (t *thriftReader) readValue(typ byte) (any, error) {
+	switch typ {
+	case thriftTrue, thriftFalse:
+		b, err := t.r.ReadByte()
+		return b == thriftTrue, err
+	case thriftByte:
+		b, err := t.r.ReadByte()
+		return int64(int8(b)), err
+	case thriftI16, thriftI32, thriftI64:
+		return t.readInt()
+	case thriftDouble:
+		var b [8]byte
+		if _, err := io.ReadFull(t.r, b[:]); err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(b[:])), nil
+	case thriftBinary:
+		n, err := t.readSize()
+		if err != nil {
+			return nil, err
+		}
+		b := make([]byte, n)
+		_, err = io.ReadFull(t.r, b)
+		return b, err
+	case thriftList, thriftSet:
+		h, err := t.r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		n := int(h >> 4)
+		if n == 15 {
+			if n, err = t.readSize(); err != nil {
+				return nil, err
+			}
+		}
+		out := make([]any, 0, min(n, t.r.Len()))
+		for i := 0; i < n; i++ {
+			v, err := t.readValue(h & 0x0f)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+		return out, nil
+	case thriftMap:
+		n, err := t.readSize()
+		if err != nil || n == 0 {
+			return nil, err
+		}
+		kv, err := t.r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < n; i++ {
+			if _, err := t.readValue(kv >> 4); err != nil {
+				return nil, err
+			}
+			if _, err := t.readValue(kv & 0x0f); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	case thriftStruct:
+		return t.readStruct()
+	}
+	return nil, errThrift
+}
@@ -11,9 +11,8 @@ import (
 func UnixNow() int64 { return time.Now().Unix() }
 
 // ToUnixSeconds attempts to normalize inputs to unix seconds.
-// Accepts strings like "now", decimal seconds, or ISO8601 that Go parses.
-// For this MVP, support only "now" and integer strings; callers can pass nil
-// to indicate now for optional timestamps.
+// Accepts "now", integer seconds, RFC 3339 timestamps (seconds optional, e.g.
+// "2026-10-01T00:00Z") and plain dates ("2026-10-01", UTC).
 func ToUnixSeconds(s string) (int64, bool) {
 	if s == "now" {
 		return UnixNow(), true
@@ -21,6 +20,11 @@ func ToUnixSeconds(s string) (int64, bool) {
 	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
 		return n, true
 	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04Z07:00", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Unix(), true
+		}
+	}
 	return 0, false
 }
 
@@ -0,0 +1,109 @@
+package flightradar
+
+import (
+	"fmt"
+	"strings"
+
+	pb "github.com/igolaizola/fr24/pkg/proto"
+)
+
+var (
+	allDataSources = []pb.DataSource{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	allServices    = []pb.Service{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}
+)
+
+// settings builds the VisibilitySettings for a live feed request. Empty
+// source and service lists and a zero traffic type select everything.
+func (p LiveFeedParams) settings() *pb.VisibilitySettings {
+	sources := p.Sources
+	if len(sources) == 0 {
+		sources = allDataSources
+	}
+	services := p.Services
+	if len(services) == 0 {
+		services = allServices
+	}
+	traffic := p.Traffic
+	if traffic == pb.TrafficType_NONE {
+		traffic = pb.TrafficType_ALL
+	}
+	return &pb.VisibilitySettings{
+		SourcesList:    sources,
+		ServicesList:   services,
+		TrafficType:    traffic,
+		OnlyRestricted: protoBool(p.OnlyRestricted),
+	}
+}
+
+func (p LiveFeedParams) validateSettings() error {
+	for _, s := range p.Sources {
+		if _, ok := pb.DataSource_name[int32(s)]; !ok {
+			return fmt.Errorf("invalid data source %d", s)
+		}
+	}
+	for _, s := range p.Services {
+		if _, ok := pb.Service_name[int32(s)]; !ok {
+			return fmt.Errorf("invalid service category %d", s)
+		}
+	}
+	if _, ok := pb.TrafficType_name[int32(p.Traffic)]; !ok {
+		return fmt.Errorf("invalid traffic type %d", p.Traffic)
+	}
+	return nil
+}
+
+// ParseDataSource parses a data source name such as "adsb", "mlat", "flarm",
+// "faa", "estimated", "satellite", "uat", "spidertracks", "aus" or "other".
+func ParseDataSource(s string) (pb.DataSource, error) {
+	key := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(s), "-", "_"))
+	switch key {
+	case "ADS_B":
+		return pb.DataSource_ADSB, nil
+	case "SAT":
+		return pb.DataSource_SATELLITE, nil
+	case "OTHER":
+		return pb.DataSource_OTHER_DATA_SOURCE, nil
+	}
+	if v, ok := pb.DataSource_value[key]; ok {
+		return pb.DataSource(v), nil
+	}
+	return 0, fmt.Errorf("unknown data source %q", s)
+}
+
+// ParseDataSources parses a comma-separated list of data source names.
+func ParseDataSources(s string) ([]pb.DataSource, error) {
+	return parseList(s, ParseDataSource)
+}
+
+// ParseServices parses a comma-separated list of service category names.
+func ParseServices(s string) ([]pb.Service, error) {
+	return parseList(s, ParseService)
+}
+
+// ParseTrafficType parses "all", "airborne" or "ground".
+func ParseTrafficType(s string) (pb.TrafficType, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "all":
+		return pb.TrafficType_ALL, nil
+	case "airborne", "airborne_only":
+		return pb.TrafficType_AIRBORNE_ONLY, nil
+	case "ground", "ground_only":
+		return pb.TrafficType_GROUND_ONLY, nil
+	}
+	return 0, fmt.Errorf("unknown traffic type %q: want all, airborne or ground", s)
+}
+
+func parseList[T any](s string, parse func(string) (T, error)) ([]T, error) {
+	var out []T
+	for _, part := range strings.Split(s, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		v, err := parse(part)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
@@ -0,0 +1,84 @@
+package flightradartest
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// jsonFixtures maps fixture file names to JSON endpoint paths.
+var jsonFixtures = map[string]string{
+	"flight_list.json":     PathFlightList,
+	"airport.json":         PathAirport,
+	"flight_playback.json": PathPlayback,
+	"find.json":            PathFind,
+	"login.json":           PathLogin,
+}
+
+// LoadFixtures sets responses from the files at the root of fsys:
+//
+//   - <Method>.json, e.g. LiveFeed.json, holds the protojson response of a
+//     Feed RPC. For streaming RPCs such as FollowFlight.json each non-empty
+//     line is one message.
+//   - flight_list.json, airport.json, flight_playback.json, find.json and
+//     login.json are served as is by the JSON endpoints.
+//
+// Other files are ignored.
+func (s *Server) LoadFixtures(fsys fs.FS) error {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || path.Ext(name) != ".json" {
+			continue
+		}
+		b, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		if p, ok := jsonFixtures[name]; ok {
+			s.SetJSON(p, b)
+			continue
+		}
+		method := strings.TrimSuffix(name, ".json")
+		md := methodDescriptor(method)
+		if md == nil {
+			continue
+		}
+		var msgs []proto.Message
+		chunks := [][]byte{b}
+		if md.IsStreamingServer() {
+			chunks = nil
+			sc := bufio.NewScanner(bytes.NewReader(b))
+			sc.Buffer(nil, 64<<20)
+			for sc.Scan() {
+				if ln := bytes.TrimSpace(sc.Bytes()); len(ln) > 0 {
+					chunks = append(chunks, append([]byte(nil), ln...))
+				}
+			}
+			if err := sc.Err(); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+		for _, c := range chunks {
+			m, err := newMessage(md.Output())
+			if err != nil {
+				return err
+			}
+			if err := protojson.Unmarshal(c, m); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			msgs = append(msgs, m)
+		}
+		s.SetResponse(method, msgs...)
+	}
+	return nil
+}
@@ -0,0 +1,348 @@
+// Package flightradartest provides a fake Flightradar24 server for offline
+// tests. It speaks gRPC-web for every RPC of the Feed service in v1.proto,
+// including server streams, and serves the JSON endpoints used by the
+// client (flight list, airport, playback, find and login).
+//
+// Responses come from fixtures (SetResponse, SetJSON, LoadFixtures) or from
+// scripted handlers (HandleUnary, HandleStream, HandleJSON):
+//
+//	srv := flightradartest.NewServer()
+//	defer srv.Close()
+//	srv.SetResponse("TopFlights", &pb.TopFlightsResponse{...})
+//	c := srv.Client()
+//	resp, err := c.TopFlights(ctx, flightradar.TopFlightsParams{Limit: 5})
+package flightradartest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+
+	lib "github.com/igolaizola/fr24/pkg/flightradar"
+	pb "github.com/igolaizola/fr24/pkg/proto"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// JSON endpoint paths, relative to the API or Web base URL.
+const (
+	PathFlightList = "/common/v1/flight/list.json"
+	PathAirport    = "/common/v1/airport.json"
+	PathPlayback   = "/common/v1/flight-playback.json"
+	PathFind       = "/v1/search/web/find"
+	PathLogin      = "/user/login"
+)
+
+// grpcPrefix is the path prefix of every Feed RPC.
+const grpcPrefix = "/fr24.feed.api.v1.Feed/"
+
+// UnaryFunc answers a unary RPC. Return a *Status to choose the gRPC status
+// code; any other error is reported as Unknown.
+type UnaryFunc func(ctx context.Context, req proto.Message) (proto.Message, error)
+
+// StreamFunc answers a server-streaming RPC by calling send for each message.
+// Each message is flushed to the client immediately. The returned error
+// becomes the trailer status.
+type StreamFunc func(ctx context.Context, req proto.Message, send func(proto.Message) error) error
+
+// Status is a gRPC status returned by a handler.
+type Status struct {
+	Code    int
+	Message string
+}
+
+func (s *Status) Error() string { return fmt.Sprintf("grpc status %d: %s", s.Code, s.Message) }
+
+// Errorf returns a *Status with the given code.
+func Errorf(code int, format string, args ...any) *Status {
+	return &Status{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// gRPC status codes used by the server.
+const (
+	CodeUnknown         = 2
+	CodeInvalidArgument = 3
+	CodeNotFound        = 5
+	CodeUnimplemented   = 12
+)
+
+// Request is a request received by the server.
+type Request struct {
+	// Method is the RPC name for gRPC calls and the URL path otherwise.
+	Method string
+	Query  url.Values
+	Header http.Header
+	// Message is the decoded request of a gRPC call.
+	Message proto.Message
+	// Body is the raw body of a JSON endpoint call.
+	Body []byte
+}
+
+// Server is a fake FR24 server. All methods are safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	unary    map[string]UnaryFunc
+	streams  map[string]StreamFunc
+	json     map[string]http.HandlerFunc
+	requests []Request
+}
+
+// NewServer starts a server with default handlers for Echo, CountDown and
+// login. Other RPCs answer Unimplemented and other paths 404 until a fixture
+// or handler is set.
+func NewServer() *Server {
+	s := &Server{
+		unary:   map[string]UnaryFunc{},
+		streams: map[string]StreamFunc{},
+		json:    map[string]http.HandlerFunc{},
+	}
+	s.HandleUnary("Echo", func(_ context.Context, req proto.Message) (proto.Message, error) {
+		p := req.(*pb.Ping)
+		return &pb.Pong{C: p.GetA() + p.GetB()}, nil
+	})
+	s.HandleStream("CountDown", func(ctx context.Context, req proto.Message, send func(proto.Message) error) error {
+		for n := req.(*pb.Duration).GetCount(); n > 0; n-- {
+			if err := send(&pb.Tick{Count: n}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	s.SetJSON(PathLogin, []byte(`{"success":true,"status":"success","message":"Login successful",`+
+		`"userData":{"subscriptionKey":"test-subscription-key","accessToken":"test-access-token"}}`))
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Endpoints returns endpoints that send every call to the server.
+func (s *Server) Endpoints() lib.Endpoints {
+	return lib.Endpoints{GRPC: s.URL, API: s.URL, Web: s.URL}
+}
+
+// Client returns a client pointed at the server.
+func (s *Server) Client() *lib.Client {
+	return lib.New().WithHTTP(s.Server.Client()).WithEndpoints(s.Endpoints())
+}
+
+// HandleUnary sets the handler of a unary RPC, e.g. "LiveFeed".
+func (s *Server) HandleUnary(method string, h UnaryFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unary[method] = h
+}
+
+// HandleStream sets the handler of a server-streaming RPC, e.g.
+// "FollowFlight".
+func (s *Server) HandleStream(method string, h StreamFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.streams[method] = h
+}
+
+// SetResponse answers method with fixed messages: the first one for a unary
+// RPC, all of them in order for a stream.
+func (s *Server) SetResponse(method string, msgs ...proto.Message) {
+	md := methodDescriptor(method)
+	if md != nil && md.IsStreamingServer() {
+		s.HandleStream(method, func(_ context.Context, _ proto.Message, send func(proto.Message) error) error {
+			for _, m := range msgs {
+				if err := send(m); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		return
+	}
+	s.HandleUnary(method, func(context.Context, proto.Message) (proto.Message, error) {
+		if len(msgs) == 0 {
+			return nil, Errorf(CodeNotFound, "no response")
+		}
+		return msgs[0], nil
+	})
+}
+
+// HandleJSON sets the handler of a JSON endpoint path such as PathFind.
+func (s *Server) HandleJSON(path string, h http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.json[path] = h
+}
+
+// SetJSON answers path with a fixed JSON body.
+func (s *Server) SetJSON(path string, body []byte) {
+	s.HandleJSON(path, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	})
+}
+
+// Requests returns the requests received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) record(r Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if method, ok := strings.CutPrefix(r.URL.Path, grpcPrefix); ok {
+		s.serveGRPC(w, r, method)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	s.record(Request{Method: r.URL.Path, Query: r.URL.Query(), Header: r.Header.Clone(), Body: body})
+	s.mu.Lock()
+	h := s.json[r.URL.Path]
+	s.mu.Unlock()
+	if h == nil {
+		http.NotFound(w, r)
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	h(w, r)
+}
+
+func (s *Server) serveGRPC(w http.ResponseWriter, r *http.Request, method string) {
+	md := methodDescriptor(method)
+	if md == nil {
+		writeStatus(w, Errorf(CodeUnimplemented, "unknown method %s", method))
+		return
+	}
+	req, err := decodeRequest(r.Body, md)
+	if err != nil {
+		writeStatus(w, Errorf(CodeInvalidArgument, "%v", err))
+		return
+	}
+	s.record(Request{Method: method, Query: r.URL.Query(), Header: r.Header.Clone(), Message: req})
+
+	s.mu.Lock()
+	unary, stream := s.unary[method], s.streams[method]
+	s.mu.Unlock()
+	switch {
+	case md.IsStreamingServer() && stream != nil:
+		s.serveStream(w, r, req, stream)
+	case !md.IsStreamingServer() && unary != nil:
+		resp, err := unary(r.Context(), req)
+		if err != nil {
+			writeStatus(w, err)
+			return
+		}
+		b, err := proto.Marshal(resp)
+		if err != nil {
+			writeStatus(w, err)
+			return
+		}
+		writeHeaders(w)
+		_, _ = w.Write(lib.Frame{Payload: b}.Bytes())
+		_, _ = w.Write(trailer(nil))
+	default:
+		writeStatus(w, Errorf(CodeUnimplemented, "no handler for %s", method))
+	}
+}
+
+func (s *Server) serveStream(w http.ResponseWriter, r *http.Request, req proto.Message, h StreamFunc) {
+	writeHeaders(w)
+	flusher, _ := w.(http.Flusher)
+	if flusher != nil {
+		flusher.Flush()
+	}
+	err := h(r.Context(), req, func(m proto.Message) error {
+		b, err := proto.Marshal(m)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(lib.Frame{Payload: b}.Bytes()); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return r.Context().Err()
+	})
+	_, _ = w.Write(trailer(err))
+}
+
+func writeHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/grpc-web+proto")
+	w.WriteHeader(http.StatusOK)
+}
+
+// writeStatus sends a trailers-only response.
+func writeStatus(w http.ResponseWriter, err error) {
+	code, msg := statusOf(err)
+	w.Header().Set("Content-Type", "application/grpc-web+proto")
+	w.Header().Set("grpc-status", fmt.Sprint(code))
+	w.Header().Set("grpc-message", url.PathEscape(msg))
+	w.WriteHeader(http.StatusOK)
+}
+
+// trailer encodes the trailer frame for err (nil means OK).
+func trailer(err error) []byte {
+	code, msg := statusOf(err)
+	block := fmt.Sprintf("grpc-status:%d\r\ngrpc-message:%s\r\n", code, url.PathEscape(msg))
+	return lib.Frame{Flag: 0x80, Payload: []byte(block)}.Bytes()
+}
+
+func statusOf(err error) (int, string) {
+	if err == nil {
+		return 0, ""
+	}
+	var st *Status
+	if errors.As(err, &st) {
+		return st.Code, st.Message
+	}
+	return CodeUnknown, err.Error()
+}
+
+// methodDescriptor returns the Feed method called name, or nil.
+func methodDescriptor(name string) protoreflect.MethodDescriptor {
+	svc := pb.File_fr24_proto_v1_proto.Services().ByName("Feed")
+	if svc == nil {
+		return nil
+	}
+	return svc.Methods().ByName(protoreflect.Name(name))
+}
+
+// newMessage returns an empty message of type d.
+func newMessage(d protoreflect.MessageDescriptor) (proto.Message, error) {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(d.FullName())
+	if err != nil {
+		return nil, err
+	}
+	return mt.New().Interface(), nil
+}
+
+// decodeRequest reads the single message frame of a gRPC-web request.
+func decodeRequest(body io.Reader, md protoreflect.MethodDescriptor) (proto.Message, error) {
+	msg, err := newMessage(md.Input())
+	if err != nil {
+		return nil, err
+	}
+	f, err := lib.NewFrameReader(body).Next()
+	if errors.Is(err, io.EOF) {
+		return msg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if f.IsCompressed() {
+		return nil, errors.New("compressed requests are not supported")
+	}
+	return msg, proto.Unmarshal(f.Payload, msg)
+}
@@ -0,0 +1,191 @@
+package flightradartest_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"testing/fstest"
+
+	lib "github.com/igolaizola/fr24/pkg/flightradar"
+	"github.com/igolaizola/fr24/pkg/flightradartest"
+	pb "github.com/igolaizola/fr24/pkg/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestUnary(t *testing.T) {
+	srv := flightradartest.NewServer()
+	defer srv.Close()
+	want := &pb.TopFlightsResponse{ScoreboardList: []*pb.FollowedFlight{{FlightId: 962709904, Callsign: "UAE261"}}}
+	srv.SetResponse("TopFlights", want)
+
+	got, err := srv.Client().TopFlights(context.Background(), lib.TopFlightsParams{Limit: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	reqs := srv.Requests()
+	if len(reqs) != 1 || reqs[0].Method != "TopFlights" {
+		t.Fatalf("requests = %+v", reqs)
+	}
+	if limit := reqs[0].Message.(*pb.TopFlightsRequest).GetLimit(); limit != 5 {
+		t.Errorf("request limit = %d, want 5", limit)
+	}
+}
+
+func TestDefaults(t *testing.T) {
+	srv := flightradartest.NewServer()
+	defer srv.Close()
+	c := srv.Client()
+	ctx := context.Background()
+
+	pong, err := c.Ping(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pong.Pong == 0 {
+		t.Errorf("pong = %+v", pong)
+	}
+	var ticks []uint32
+	for tick, err := range c.CountDown(ctx, 3) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ticks = append(ticks, tick.GetCount())
+	}
+	if len(ticks) != 3 || ticks[0] != 3 || ticks[2] != 1 {
+		t.Errorf("ticks = %v", ticks)
+	}
+}
+
+func TestUnimplemented(t *testing.T) {
+	srv := flightradartest.NewServer()
+	defer srv.Close()
+	_, err := srv.Client().TopFlights(context.Background(), lib.TopFlightsParams{})
+	var ge *lib.GrpcError
+	if !errors.As(err, &ge) || ge.Code() != flightradartest.CodeUnimplemented {
+		t.Fatalf("got %v, want Unimplemented", err)
+	}
+}
+
+func TestFollowFlightStream(t *testing.T) {
+	frames := []*pb.FollowFlightResponse{
+		{FlightInfo: &pb.ExtendedFlightInfo{Flightid: 1, Lat: 40}},
+		{FlightInfo: &pb.ExtendedFlightInfo{Flightid: 1, Lat: 41}},
+	}
+	tests := []struct {
+		name     string
+		status   error
+		wantCode int
+	}{
+		{"ok", nil, 0},
+		{"permission denied", flightradartest.Errorf(7, "not allowed"), 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := flightradartest.NewServer()
+			defer srv.Close()
+			srv.HandleStream("FollowFlight", func(_ context.Context, req proto.Message, send func(proto.Message) error) error {
+				if id := req.(*pb.FollowFlightRequest).GetFlightId(); id != 1 {
+					return flightradartest.Errorf(flightradartest.CodeNotFound, "flight %d", id)
+				}
+				for _, f := range frames {
+					if err := send(f); err != nil {
+						return err
+					}
+				}
+				return tt.status
+			})
+
+			var got []*pb.FollowFlightResponse
+			var streamErr error
+			for msg, err := range srv.Client().GrpcFollowFlight(context.Background(), 1, pb.RestrictionVisibility_NOT_VISIBLE) {
+				if err != nil {
+					streamErr = err
+					break
+				}
+				got = append(got, msg)
+			}
+			if len(got) != len(frames) {
+				t.Fatalf("got %d frames, want %d", len(got), len(frames))
+			}
+			for i := range got {
+				if !proto.Equal(got[i], frames[i]) {
+					t.Errorf("frame %d = %v, want %v", i, got[i], frames[i])
+				}
+			}
+			if tt.wantCode == 0 {
+				if streamErr != nil {
+					t.Fatalf("unexpected error %v", streamErr)
+				}
+				return
+			}
+			var ge *lib.GrpcError
+			if !errors.As(streamErr, &ge) || ge.Code() != tt.wantCode || ge.StatusMessage != "not allowed" {
+				t.Fatalf("got %v, want status %d", streamErr, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestJSONEndpoint(t *testing.T) {
+	srv := flightradartest.NewServer()
+	defer srv.Close()
+	srv.SetJSON(flightradartest.PathFlightList, []byte(`{"result":{"response":{"data":[
+		{"identification":{"id":"3961c990","number":{"default":"EK261"},"callsign":"UAE261"},
+		 "aircraft":{"registration":"A6-EUA"}}]}}}`))
+
+	resp, err := srv.Client().FlightList(context.Background(), lib.FlightListParams{Flight: "EK261"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recs, err := lib.ParseFlightList(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 1 || recs[0].Callsign == nil || *recs[0].Callsign != "UAE261" {
+		t.Fatalf("records = %+v", recs)
+	}
+	reqs := srv.Requests()
+	if len(reqs) != 1 || reqs[0].Query.Get("query") != "EK261" || reqs[0].Query.Get("fetchBy") != "flight" {
+		t.Errorf("requests = %+v", reqs)
+	}
+}
+
+func TestLoadFixtures(t *testing.T) {
+	srv := flightradartest.NewServer()
+	defer srv.Close()
+	err := srv.LoadFixtures(fstest.MapFS{
+		"TopFlights.json":   {Data: []byte(`{"scoreboardList":[{"flightId":7,"callsign":"ABC"}]}`)},
+		"FollowFlight.json": {Data: []byte("{\"flightInfo\":{\"flightid\":7}}\n\n{\"flightInfo\":{\"flightid\":7,\"lat\":1}}\n")},
+		"README.md":         {Data: []byte("ignored")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := srv.Client()
+	top, err := c.TopFlights(context.Background(), lib.TopFlightsParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := top.GetScoreboardList()[0].GetCallsign(); got != "ABC" {
+		t.Errorf("callsign = %q", got)
+	}
+	n := 0
+	for _, err := range c.GrpcFollowFlight(context.Background(), 7, pb.RestrictionVisibility_NOT_VISIBLE) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		n++
+	}
+	if n != 2 {
+		t.Errorf("got %d stream frames, want 2", n)
+	}
+}
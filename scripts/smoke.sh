@@ -37,7 +37,7 @@ run() {
 }
 
 # Help checks
-for sub in "" version login dirs flightlist airportlist find livefeed playbackfeed nearest livestatus topflights flightdetails playbackflight followflight; do
+for sub in "" version login dirs doctor flightlist airportlist find livefeed playbackfeed nearest livestatus topflights flightdetails searchindex livetrail historictrail playbackflight followflight; do
   if [[ -z "${sub}" ]]; then
     run "help (root)" -h
   else
@@ -77,6 +77,7 @@ fi
 NEAR_ID=$(grep -o '"flightid":[0-9]\+' /tmp/fr24.nearest.out | head -n1 | tr -dc '0-9')
 if [[ -n "${NEAR_ID}" ]]; then
   run "livestatus" livestatus -id ${NEAR_ID}
+  run "livetrail" livetrail -id ${NEAR_ID}
   if [[ -n "${PB_ID}" && -n "${PB_TS}" ]]; then
     run "playbackflight" playbackflight -id ${PB_ID} -ts ${PB_TS}
   else